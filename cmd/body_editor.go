@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/lint"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// the instructions under the header in the --body-editor buffer
+const bodyEditorHelp = `# The header above was written by git-cc. Write the body and any footers
+# below; lines starting with '#' are ignored. To change the header, replace
+# the first line with an uncommented header.
+`
+
+// the buffer --body-editor opens: the header of `message` as a comment on the
+// first line, then the instructions and the rest of `message`.
+func bodyEditorBuffer(message string) string {
+	header, rest := message, ""
+	if i := strings.Index(message, "\n"); i >= 0 {
+		header, rest = message[:i], strings.TrimSpace(message[i+1:])
+	}
+	buffer := "# " + header + "\n" + bodyEditorHelp + "\n"
+	if rest != "" {
+		buffer += rest + "\n"
+	}
+	return buffer
+}
+
+// the message from an `edited` --body-editor buffer for `header`, and whether
+// the editor replaced the header. The first line is the commented header if
+// it's intact; if it's been changed into a header with a configured type, it
+// replaces `header`.
+func readBodyEditorBuffer(header string, edited string, cfg config.Cfg) (message string, replaced bool) {
+	first, rest := edited, ""
+	if i := strings.Index(edited, "\n"); i >= 0 {
+		first, rest = edited[:i], edited[i+1:]
+	}
+	first = strings.TrimSpace(first)
+	if first != "# "+header {
+		candidate := strings.TrimSpace(strings.TrimPrefix(first, "#"))
+		if cc, err := parser.ParseHeader(candidate, cfg.ParserOption()); err == nil && cc.ValidCommitType(cfg.CommitTypes) {
+			header, replaced = candidate, candidate != header
+		} else {
+			rest = edited // the first line isn't a header, so keep it as content
+		}
+	}
+	content, _ := splitComments(rest)
+	if content = strings.TrimSpace(content); content == "" {
+		return header + "\n", replaced
+	}
+	return header + "\n\n" + content + "\n", replaced
+}
+
+// open the header of `message` in `editor` via the file at `path` to write its
+// body and footers, returning the resulting message with `eol` line endings,
+// whatever the editor wrote. A message that fails `git cc lint` is an error.
+func editBody(message string, editor string, path string, eol string, cfg config.Cfg, warnings io.Writer) (string, error) {
+	header := strings.SplitN(message, "\n", 2)[0]
+	if err := config.WriteFileAtomic(path, []byte(bodyEditorBuffer(message)), 0644); err != nil {
+		return "", err
+	}
+	if err := config.EditFile(editor, path); err != nil {
+		return "", fmt.Errorf("the editor failed: %w", err)
+	}
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	result, replaced := readBodyEditorBuffer(header, config.NormalizeLineEndings(string(edited), "\n"), cfg)
+	if replaced {
+		fmt.Fprintf(warnings, "warning: using the header from the editor: %s\n", strings.SplitN(result, "\n", 2)[0])
+	}
+	if violations := lint.Lint(result, cfg); lint.Failed(violations) {
+		report := &strings.Builder{}
+		lint.Report(report, violations)
+		return "", fmt.Errorf("the edited message is invalid:\n%s\n%s", result, report)
+	}
+	return config.NormalizeLineEndings(result, eol), nil
+}
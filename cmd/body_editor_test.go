@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrippingTheBodyEditorBuffer(t *testing.T) {
+	buffer := bodyEditorBuffer("feat(cli): add x\n\nBREAKING CHANGE: y\n")
+	if !strings.HasPrefix(buffer, "# feat(cli): add x\n# ") || !strings.HasSuffix(buffer, "\n\nBREAKING CHANGE: y\n") {
+		t.Fatalf("unexpected buffer:\n%s", buffer)
+	}
+	edited := strings.Replace(buffer, "BREAKING CHANGE", "a body\n\nBREAKING CHANGE", 1)
+	message, replaced := readBodyEditorBuffer("feat(cli): add x", edited, testCfg)
+	if replaced || message != "feat(cli): add x\n\na body\n\nBREAKING CHANGE: y\n" {
+		t.Errorf("unexpected message %q (replaced: %v)", message, replaced)
+	}
+	message, replaced = readBodyEditorBuffer("feat(cli): add x", bodyEditorBuffer("feat(cli): add x"), testCfg)
+	if replaced || message != "feat(cli): add x\n" {
+		t.Errorf("unexpected message %q (replaced: %v)", message, replaced)
+	}
+}
+
+func TestChangingTheHeaderInTheBodyEditor(t *testing.T) {
+	edited := strings.Replace(bodyEditorBuffer("feat(cli): add x"), "# feat(cli): add x", "feat: add y", 1)
+	message, replaced := readBodyEditorBuffer("feat(cli): add x", edited+"a body\n", testCfg)
+	if !replaced || message != "feat: add y\n\na body\n" {
+		t.Errorf("unexpected message %q (replaced: %v)", message, replaced)
+	}
+	// a first line that isn't a header is part of the body
+	message, replaced = readBodyEditorBuffer("feat(cli): add x", "a body\n# a comment\n", testCfg)
+	if replaced || message != "feat(cli): add x\n\na body\n" {
+		t.Errorf("unexpected message %q (replaced: %v)", message, replaced)
+	}
+}
+
+func TestEditingTheBody(t *testing.T) {
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor.sh")
+	script := "#!/bin/sh\nprintf 'a body\\n\\nRefs: #12\\n' >> \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	warnings := &strings.Builder{}
+	message, err := editBody("feat(cli): add x\n", editor, filepath.Join(dir, "COMMIT_EDITMSG"), "\n", testCfg, warnings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message != "feat(cli): add x\n\na body\n\nRefs: #12\n" || warnings.Len() > 0 {
+		t.Errorf("unexpected message %q (warnings: %q)", message, warnings)
+	}
+	if _, err := editBody("feat(cli): add x\n", "false", filepath.Join(dir, "COMMIT_EDITMSG"), "\n", testCfg, warnings); err == nil {
+		t.Error("expected a failing editor to be an error")
+	}
+}
+
+func TestNormalizingTheLineEndingsOfTheBody(t *testing.T) {
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor.sh")
+	script := "#!/bin/sh\nprintf 'a body\\r\\n\\r\\nRefs: #12\\r\\n' >> \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for eol, expected := range map[string]string{
+		"\n":   "feat(cli): add x\n\na body\n\nRefs: #12\n",
+		"\r\n": "feat(cli): add x\r\n\r\na body\r\n\r\nRefs: #12\r\n",
+	} {
+		message, err := editBody("feat(cli): add x\n", editor, filepath.Join(dir, "COMMIT_EDITMSG"), eol, testCfg, &strings.Builder{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if message != expected {
+			t.Errorf("expected %q, got %q", expected, message)
+		}
+	}
+}
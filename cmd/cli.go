@@ -1,17 +1,25 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/lint"
 	"github.com/skalt/git-cc/pkg/parser"
 )
 
@@ -37,6 +45,11 @@ func getGitCommitCmd(cmd *cobra.Command) []string {
 			commitCmd = append(commitCmd, "--"+name)
 		}
 	}
+	for _, name := range stringFlags {
+		if value, _ := cmd.Flags().GetString(name); value != "" {
+			commitCmd = append(commitCmd, "--"+name+"="+value)
+		}
+	}
 	if noEdit || len(message) > 0 {
 		commitCmd = append(commitCmd, "--no-edit")
 	} else {
@@ -45,53 +58,517 @@ func getGitCommitCmd(cmd *cobra.Command) []string {
 	return commitCmd
 }
 
-// run a potentially interactive `git commit`
-func doCommit(message string, dryRun bool, commitParams []string) {
+// run a potentially interactive `git commit`, returning git's stderr if it fails.
+func doCommit(message string, dryRun bool, commitParams []string) (string, error) {
 	f := config.GetCommitMessageFile()
-	file, err := os.Create(f)
-	if err != nil {
-		log.Fatalf("unable to create %s: %+v", f, err)
-	}
-	_, err = file.Write([]byte(message))
-	if err != nil {
-		log.Fatalf("unable to write to %s: %+v", f, err)
+	if err := config.WriteFileAtomic(f, []byte(message), 0644); err != nil {
+		log.Fatal(err)
 	}
 	if dryRun {
 		fmt.Println(message)
 	}
-	cmd := append([]string{"git", "commit", "--message", message}, commitParams...)
-	process := exec.Command(cmd[0], cmd[1:]...)
-	process.Stdin = os.Stdin
-	process.Stdout = os.Stdout
-	process.Stderr = os.Stderr
+	args := append([]string{"commit", "--message", message}, commitParams...)
+	cmd := append([]string{config.GitCommand}, args...)
 	if dryRun {
 		fmt.Printf("would run: `%s`\n", strings.Join(cmd, " "))
-		os.Exit(0)
-	} else {
-		err = process.Run()
+		return "", nil
+	}
+	stderr := &bytes.Buffer{}
+	err := config.Runner.Run(os.Stdin, os.Stdout, stderr, args...)
+	if err != nil {
+		err = fmt.Errorf("failed running `%+v`: %+v", cmd, err)
+	}
+	return stderr.String(), err
+}
+
+// run `message` through the message_filter, if any, checking that a changed
+// message still passes `git cc lint`.
+func filterMessage(message string, cfg config.Cfg) (string, error) {
+	filtered, err := cfg.FilterMessage(message)
+	if err != nil || filtered == message {
+		return filtered, err
+	}
+	if violations := lint.Lint(filtered, cfg); lint.Failed(violations) {
+		report := &strings.Builder{}
+		lint.Report(report, violations)
+		return "", fmt.Errorf("message_filter produced an invalid message:\n%s\n%s", filtered, report)
+	}
+	return filtered, nil
+}
+
+// returned by commitAndPush when the commit succeeded but pushing it didn't
+var errPushFailed = errors.New("committed, but pushing failed")
+
+// the command to push with after committing: push_command if configured, or
+// else `git push`, setting the upstream to origin if `setUpstream`.
+func pushCommand(cfg config.Cfg, setUpstream bool) []string {
+	switch {
+	case cfg.PushCommand != "":
+		return []string{"sh", "-c", cfg.PushCommand}
+	case setUpstream:
+		return []string{config.GitCommand, "push", "-u", "origin", "HEAD"}
+	default:
+		return []string{config.GitCommand, "push"}
+	}
+}
+
+// commit `message`, then run `push` if it's non-nil. A failed push doesn't
+// undo the commit.
+func commitAndPush(message string, dryRun bool, commitParams []string, push []string) (string, error) {
+	stderr, err := doCommit(message, dryRun, commitParams)
+	if err != nil || push == nil {
+		return stderr, err
+	}
+	fmt.Fprint(os.Stderr, stderr)
+	if dryRun {
+		fmt.Printf("would run: `%s`\n", strings.Join(push, " "))
+		return "", nil
+	}
+	if err := runCommand(push, os.Stdin, os.Stderr, os.Stderr); err != nil {
+		return "", fmt.Errorf("%w: `%s`: %v", errPushFailed, strings.Join(push, " "), err)
+	}
+	return "", nil
+}
+
+// run `command`, sending git commands through the config.Runner
+func runCommand(command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if command[0] == config.GitCommand {
+		return config.Runner.Run(stdin, stdout, stderr, command[1:]...)
+	}
+	process := exec.Command(command[0], command[1:]...)
+	process.Stdin, process.Stdout, process.Stderr = stdin, stdout, stderr
+	return process.Run()
+}
+
+// show why git failed and ask whether to edit the message and try again.
+func promptRetry(in io.Reader, out io.Writer, gitStderr string, gitErr error) bool {
+	fmt.Fprintf(out, "%s%v\n", gitStderr, gitErr)
+	return confirm(in, out, "edit the message and retry?")
+}
+
+// ask a yes-or-no `question`, where yes is the default.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [Y/n] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// commit `message`, re-opening the TUI with the message's fields preserved
+// each time git fails until the commit succeeds or the user aborts. Then run
+// `push`, if it's non-nil. The message is kept as a draft for --resume until
+// it's committed.
+func commitWithRetries(message string, cfg config.Cfg, dryRun bool, commitParams []string, push []string) {
+	for {
+		filtered, err := filterMessage(message, cfg)
 		if err != nil {
-			log.Fatalf("failed running `%+v`: %+v", cmd, err)
-		} else {
+			log.Fatal(err)
+		}
+		message = filtered
+		stderr, err := commitAndPush(message, dryRun, commitParams, push)
+		if (err == nil && !dryRun) || errors.Is(err, errPushFailed) {
+			clearDraft(draftPath())
+		}
+		if errors.Is(err, errPushFailed) {
+			log.Fatal(err)
+		}
+		if err == nil {
+			fmt.Fprint(os.Stderr, stderr)
 			os.Exit(0)
 		}
+		if err := saveDraft(draftPath(), message); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to save a draft of the message: %v\n", err)
+		}
+		if !promptRetry(os.Stdin, os.Stderr, stderr, err) {
+			log.Fatal(err)
+		}
+		cc, _ := parser.ParseAsMuchOfCCAsPossible(message, cfg.ParserOption())
+		readScopeSuffix(cc, cfg)
+		if message = runTUI(cc, cfg); message == "" {
+			os.Exit(1) // no submission
+		}
+	}
+}
+
+// resolve the configuration, applying any config-related flags.
+func loadConfig(cmd *cobra.Command) config.Cfg {
+	if err := config.SetLanguage(config.CatalogDir(), config.LanguageFromEnv()); err != nil {
+		log.Fatal(err)
+	}
+	dir := "."
+	if fromStaged, _ := cmd.Flags().GetBool("config-from-staged"); fromStaged {
+		if staged := stagedDir(); staged != "" {
+			dir = staged
+		}
+	}
+	paths, _ := cmd.Flags().GetStringArray("config-path")
+	noWalk, _ := cmd.Flags().GetBool("no-walk")
+	var store *viper.Viper
+	if noConfig, _ := cmd.Flags().GetBool("no-config"); noConfig {
+		if len(paths) > 0 {
+			log.Fatal("--no-config and --config-path are mutually exclusive")
+		}
+		store = config.InitDefaults()
+	} else {
+		var err error
+		if store, err = config.InitSearching(dir, paths, !noWalk); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+		store.Set("profile", profile)
+	}
+	if scopeLast, _ := cmd.Flags().GetBool("scope-last"); scopeLast {
+		store.Set("scope_position", config.ScopeSuffix)
+	}
+	if minimal, _ := cmd.Flags().GetBool("minimal"); minimal {
+		store.Set("minimal", true)
+	}
+	enforce, _ := cmd.Flags().GetBool("enforce-length")
+	noEnforce, _ := cmd.Flags().GetBool("no-enforce-length")
+	if enforce && noEnforce {
+		log.Fatal("--enforce-length and --no-enforce-length are mutually exclusive")
+	} else if enforce || noEnforce {
+		store.Set("enforce_header_max_length", enforce)
+	}
+	cfg := config.Lookup(store)
+	if warning := cfg.SeparatorWarning(); warning != "" {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	for _, warning := range cfg.DuplicateWarnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s; using the first\n", warning)
+	}
+	return cfg
+}
+
+// matches abbreviated or full commit hashes
+var commitHash = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+
+// describes a placeholder_descriptions match, e.g. "wip", followed by what to
+// do about it
+func placeholderErr(placeholder string, blocking bool) error {
+	if blocking {
+		return config.WithHint(config.Error(config.WarningPlaceholder, placeholder), config.HintDescribeChange)
+	}
+	return config.WithHint(config.Error(config.WarningPlaceholder, placeholder), config.HintSubmitAgain)
+}
+
+// suggests the imperative if subject_mood is imperative and `description`
+// starts with e.g. "added", or returns "" otherwise
+func moodHint(description string, cfg config.Cfg) string {
+	word, imperative := cfg.MoodSuggestion(description)
+	if word == "" {
+		return ""
+	}
+	return fmt.Sprintf("'%s' isn't imperative; consider '%s'", word, imperative)
+}
+
+// whether a commit of type `commitType` is a revert that doesn't mention the
+// commit it reverts in its body or footers.
+func missingRevertRef(commitType string, body string, footers []string) bool {
+	if commitType != "revert" {
+		return false
+	}
+	for _, footer := range footers {
+		if strings.EqualFold(parser.FooterTokenOf(footer), "Refs") {
+			return false
+		}
+	}
+	return !commitHash.MatchString(body + "\n" + strings.Join(footers, "\n"))
+}
+
+// the deepest directory containing each of `files`, which are relative,
+// slash-separated paths like git prints.
+func commonDir(files []string) string {
+	var common []string
+	for i, file := range files {
+		dirs := strings.Split(path.Dir(file), "/")
+		if i == 0 {
+			common = dirs
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(dirs) && common[n] == dirs[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return path.Clean(strings.Join(common, "/"))
+}
+
+// the deepest directory containing every staged file, or "" if nothing is
+// staged.
+func stagedDir() string {
+	root, err := config.Runner.Output("rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	// -z keeps git from quoting unusual paths
+	out, err := config.Runner.Output("diff", "--name-only", "--cached", "-z")
+	if err != nil {
+		return ""
+	}
+	files := []string{}
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return ""
+	}
+	return filepath.Join(strings.TrimSpace(root), filepath.FromSlash(commonDir(files)))
+}
+
+// in the scope-last layout, read the scope from the end of the description
+func readScopeSuffix(cc *parser.CC, cfg config.Cfg) {
+	if cfg.ScopePosition == config.ScopeSuffix && cc.Scope == "" {
+		cc.Description, cc.Scope = parser.SplitScopeSuffix(cc.Description)
+	}
+}
+
+// format a commit in the configured layout
+func formatCommit(cc *parser.CC, cfg config.Cfg) string {
+	if cfg.ScopePosition == config.ScopeSuffix {
+		moved := *cc
+		moved.Description = parser.JoinScopeSuffix(cc.Description, cc.Scope)
+		moved.Scope = ""
+		return moved.ToString(cfg.ParserOption())
+	}
+	return cc.ToString(cfg.ParserOption())
+}
+
+// format `cc` for -m, where there's no editor to stop typing at the limit.
+// Headers longer than header_max_length are an error if the limit is
+// enforced, unless on_max_length is "truncate", which cuts the description to
+// fit. Otherwise they're only a warning.
+func formatWithinLimit(cc *parser.CC, cfg config.Cfg) (formatted string, warning string, err error) {
+	formatted = formatCommit(cc, cfg)
+	header := strings.SplitN(formatted, "\n", 2)[0]
+	err = cfg.CheckHeaderLength(header)
+	switch {
+	case err == nil:
+		return formatted, "", nil
+	case !cfg.EnforceMaxLength:
+		return formatted, err.Error(), nil
+	case cfg.OnMaxLength != config.OnMaxLengthTruncate:
+		return "", "", err
+	}
+	description := cc.Description
+	if cc.Description = cfg.TruncateDescription(header, description); cc.Description == "" {
+		cc.Description = description
+		return "", "", fmt.Errorf("%w, leaving no room for the description", err)
+	}
+	return formatCommit(cc, cfg), fmt.Sprintf("%v; cut the description to %q", err, cc.Description), nil
+}
+
+// pre-select the type and scope named by the current branch, if enabled and
+// not already given.
+func readBranch(cc *parser.CC, cfg config.Cfg, branch string) {
+	if !cfg.TypeFromBranch || cc.Type != "" {
+		return
+	}
+	cc.Type, cc.Scope = cfg.FromBranch(branch)
+}
+
+// the width of diff stats in the body, matching git's conventional body width
+const diffStatWidth = 72
+
+// a summary of the changes to be committed, or "" if git can't produce one
+func diffStat(all bool) string {
+	base := "--cached"
+	if all {
+		base = "HEAD"
+	}
+	out, err := config.Runner.Output("diff", base, fmt.Sprintf("--stat=%d", diffStatWidth))
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// append a diff stat to the body unless it's empty or already there
+func appendDiffStat(body string, stat string) string {
+	stat = strings.TrimRight(stat, " \t\r\n")
+	if strings.TrimSpace(stat) == "" || strings.Contains(body, stat) {
+		return body
+	}
+	if body == "" {
+		return stat
+	}
+	return body + "\n\n" + stat
+}
+
+// apply the configured casing rules to a commit parsed from the command line
+func normalizeCase(cc *parser.CC, cfg config.Cfg) {
+	cc.Type = cfg.NormalizeType(cc.Type)
+	cc.Scope = config.ApplyScopeCase(cfg.ScopeCase, cc.Scope)
+	cc.Description = config.ApplyCase(cfg.SubjectCase, cc.Description)
+}
+
+// check a commit type passed with --type, suggesting the closest configured
+// types if it's unknown.
+func checkTypeFlag(commitType string, cfg config.Cfg) error {
+	cc := parser.CC{Type: commitType}
+	if cc.ValidCommitType(cfg.CommitTypes) {
+		return nil
+	}
+	err := config.Error(config.ErrorUnknownType, commitType)
+	if suggestion := lint.DidYouMean(commitType, cfg.CommitTypes); suggestion != "" {
+		err = fmt.Errorf("%w -- %s", err, suggestion)
+	}
+	return err
+}
+
+// seed a commit from an existing message. Messages that aren't conventional
+// commits keep their subject as the description and the rest as the body.
+func seedFromMessage(message string, opts ...parser.Option) *parser.CC {
+	cc, err := parser.ParseAsMuchOfCCAsPossible(message, opts...)
+	if err == nil && parser.IsSingleToken(cc.Type) {
+		return cc
+	}
+	lines := strings.SplitN(strings.TrimSpace(message), "\n", 2)
+	cc = &parser.CC{Description: strings.TrimSpace(lines[0])}
+	if len(lines) > 1 {
+		cc.Body = strings.TrimSpace(lines[1])
+	}
+	return cc
+}
+
+// whether to check that messages parse back to the fields they were built
+// from before committing; see checkRoundTrip.
+var roundTripCheck = true
+
+// returns an error if `message` doesn't parse back to the `expected` type,
+// scope, description, and breaking-change status, e.g. because a scope
+// contains a `)`.
+func checkRoundTrip(message string, expected parser.CC, cfg config.Cfg) error {
+	if !roundTripCheck {
+		return nil
+	}
+	actual, _ := parser.ParseAsMuchOfCCAsPossible(message, cfg.ParserOption())
+	readScopeSuffix(actual, cfg)
+	fields := []struct {
+		name             string
+		actual, expected string
+	}{
+		{"type", actual.Type, expected.Type},
+		{"scope", actual.Scope, expected.Scope},
+		{"description", actual.Description, strings.TrimSpace(expected.Description)},
+		{
+			"breaking change",
+			fmt.Sprint(actual.BreakingChange),
+			fmt.Sprint(expected.BreakingChange),
+		},
+	}
+	for _, field := range fields {
+		if field.actual != field.expected {
+			return fmt.Errorf(
+				"aborting: the %s would be read back as %q instead of %q from:\n%s\n"+
+					"(use --skip-round-trip-check to commit anyway)",
+				field.name, field.actual, field.expected, message,
+			)
+		}
+	}
+	return nil
+}
+
+// split the `args` after `--` off as pathspecs, like `git commit -- <paths>`.
+func splitPathspecs(cmd *cobra.Command, args []string) (message []string, paths []string) {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		return args[:dash], args[dash:]
+	}
+	return args, nil
+}
+
+// check that each of the `paths` exists or matches a file git tracks, such as
+// a deleted file or a glob.
+func checkPathspecs(paths []string) error {
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil {
+			continue
+		}
+		if _, err := config.Runner.Output("ls-files", "--error-unmatch", "--", path); err != nil {
+			return fmt.Errorf("pathspec '%s' did not match any files", path)
+		}
 	}
+	return nil
+}
+
+// the `git commit` parameters committing only the `paths`, which git expects
+// after every flag.
+func withPathspecs(commitParams []string, paths []string) []string {
+	if len(paths) == 0 {
+		return commitParams
+	}
+	return append(append(append([]string{}, commitParams...), "--"), paths...)
+}
+
+// whether to abort when nothing is staged. Dry runs, commits of all changes,
+// and empty commits don't need staged changes.
+func requiresStagedChanges(cmd *cobra.Command) bool {
+	for _, name := range []string{"dry-run", "all", "allow-empty"} {
+		if set, _ := cmd.Flags().GetBool(name); set {
+			return false
+		}
+	}
+	return true
+}
+
+// when nothing is staged, list the changes `git add -A` would stage and stage
+// them, asking first if `ask` is set. Returns an error if nothing is staged.
+func stageAllChanges(git config.GitRunner, ask bool, in io.Reader, out io.Writer) error {
+	status, err := git.Output("status", "--short", "--untracked-files=all")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) == "" {
+		return errors.New("No files staged, and there are no changes to stage")
+	}
+	fmt.Fprintf(out, "nothing is staged; `git add -A` would stage:\n%s", status)
+	if ask && !confirm(in, out, "stage all of these changes?") {
+		return errors.New("No files staged")
+	}
+	_, err = git.Output("add", "-A")
+	return err
 }
 
 // run the conventional-commit helper logic. This may/not break into the TUI.
 func mainMode(cmd *cobra.Command, args []string) {
-	cfg := config.Lookup(config.Init())
-	commitParams := getGitCommitCmd(cmd)
+	cfg := loadConfig(cmd)
+	skipCheck, _ := cmd.Flags().GetBool("skip-round-trip-check")
+	roundTripCheck = !skipCheck
+	args, paths := splitPathspecs(cmd, args)
+	if err := checkPathspecs(paths); err != nil {
+		log.Fatal(err)
+	}
+	commitParams := withPathspecs(getGitCommitCmd(cmd), paths)
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	committingAllChanges, _ := cmd.Flags().GetBool("all")
-	if !dryRun && !committingAllChanges {
-		buf := &bytes.Buffer{}
-		process := exec.Command("git", "diff", "--name-only", "--cached")
-		process.Stdout = buf
-		err := process.Run()
+	var push []string
+	pushing, _ := cmd.Flags().GetBool("push")
+	if setUpstream, _ := cmd.Flags().GetBool("set-upstream"); pushing || setUpstream {
+		push = pushCommand(cfg, setUpstream)
+	}
+	// git stages the pathspecs itself
+	if len(paths) == 0 && requiresStagedChanges(cmd) {
+		staged, err := config.Runner.Output("diff", "--name-only", "--cached")
 		if err != nil {
 			log.Fatalf("fatal: not a git repository (or any of the parent directories): .git; %+v", err)
 		}
-		if buf.String() == "" {
+		autoStage, _ := cmd.Flags().GetBool("auto-stage")
+		if staged == "" && (autoStage || cfg.AutoStageWhenEmpty) {
+			if !autoStage && !isTerminal(os.Stdin) {
+				log.Fatal("No files staged; pass --auto-stage to stage all changes without asking")
+			}
+			if err := stageAllChanges(config.Runner, !autoStage, os.Stdin, os.Stderr); err != nil {
+				log.Fatal(err)
+			}
+		} else if staged == "" {
 			log.Fatal("No files staged")
 		}
 	}
@@ -99,46 +576,271 @@ func mainMode(cmd *cobra.Command, args []string) {
 	var cc *parser.CC
 
 	message, _ := cmd.Flags().GetStringArray("message")
+	reuse, _ := cmd.Flags().GetString("reuse-message")
+	if len(message) == 0 && len(args) == 0 && reuse == "" && config.MergeInProgress() {
+		commitMerge(dryRun, commitParams)
+	}
+	if params, err := fixupParams(cmd); err != nil {
+		log.Fatal(err)
+	} else if params != nil {
+		commitWithGitsMessage(dryRun, withPathspecs(params, paths))
+	}
 
-	if len(message) > 0 {
-		cc, _ = parser.ParseAsMuchOfCCAsPossible(strings.Join(message, "\n\n"))
+	if freeform, _ := cmd.Flags().GetBool("freeform"); freeform {
+		result := freeformMessage(message, args, os.Stdin, os.Stderr)
+		if result == "" {
+			os.Exit(1) // no subject
+		}
+		stderr, err := commitAndPush(result, dryRun, commitParams, push)
+		fmt.Fprint(os.Stderr, stderr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	resume, _ := cmd.Flags().GetBool("resume")
+	fresh := len(message) == 0 && len(args) == 0 && reuse == ""
+	draft := restoreDraft(draftPath(), resume, fresh && !needsPlainPrompts(), os.Stdin, os.Stderr)
+	if draft != "" {
+		cc = seedFromMessage(draft, cfg.ParserOption())
+	} else if reuse != "" {
+		reused, err := config.CommitMessage(reuse)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cc = seedFromMessage(reused, cfg.ParserOption())
+	} else if len(message) > 0 {
+		cc, _ = parser.ParseWithFooterMarker(strings.Join(message, "\n\n"), cfg.FooterMarker, cfg.ParserOption())
 	} else {
-		cc, _ = parser.ParseAsMuchOfCCAsPossible((strings.Join(args, " ")))
+		cc, _ = parser.ParseWithFooterMarker(strings.Join(args, " "), cfg.FooterMarker, cfg.ParserOption())
+	}
+	if commitType, _ := cmd.Flags().GetString("type"); commitType != "" {
+		commitType = cfg.NormalizeType(commitType)
+		if err := checkTypeFlag(commitType, cfg); err != nil {
+			log.Fatal(err)
+		}
+		cc.Type = commitType
+	}
+	if reverted, _ := cmd.Flags().GetString("revert"); reverted != "" {
+		if cc.Type == "" {
+			cc.Type = "revert"
+		}
+		cc.Footers = parser.MergeFooters(cc.Footers, []string{"Refs: " + reverted})
 	}
-	valid := cc.MinimallyValid() &&
+	readScopeSuffix(cc, cfg)
+	readBranch(cc, cfg, config.CurrentBranch())
+	normalizeCase(cc, cfg)
+	if err := cfg.CheckTypeCase(cc.Type); err != nil {
+		log.Fatal(err)
+	}
+	if include, _ := cmd.Flags().GetBool("diff-stat"); include || cfg.IncludeDiffStat {
+		cc.Body = appendDiffStat(cc.Body, diffStat(committingAllChanges))
+	}
+	valid := reuse == "" && draft == "" && // always edit reused messages and drafts
+		cc.MinimallyValid() &&
 		cc.ValidCommitType(cfg.CommitTypes) &&
 		(cc.ValidScope(cfg.Scopes) || cc.Scope == "")
-	if !valid {
-		choice := make(chan string, 1)
-		m := initialModel(choice, cc, cfg)
-		ui := tea.NewProgram(m)
-		if err := ui.Start(); err != nil {
+	if protocol, _ := cmd.Flags().GetBool("protocol"); protocol {
+		// steps that are already valid are skipped; git's output follows the
+		// composed message
+		result := runProtocol(cc, cfg, os.Stdin, os.Stdout)
+		if result == "" {
+			os.Exit(1) // no submission
+		}
+		result, err := filterMessage(result, cfg)
+		if err != nil {
 			log.Fatal(err)
 		}
-		if result := <-choice; result == "" {
-			close(choice)
-			os.Exit(1) // no submission
+		stderr, err := commitAndPush(result, dryRun, commitParams, push)
+		fmt.Fprint(os.Stderr, stderr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+	if !valid {
+		var result string
+		if plain, _ := cmd.Flags().GetBool("plain"); plain || needsPlainPrompts() {
+			result = runPlain(cc, cfg, os.Stdin, os.Stderr)
 		} else {
-			f := config.GetCommitMessageFile()
-			file, err := os.Create(f)
+			result = runTUI(cc, cfg)
+		}
+		if result == "" {
+			os.Exit(1) // no submission
+		}
+		f := config.GetCommitMessageFile()
+		if bodyEditor, _ := cmd.Flags().GetBool("body-editor"); bodyEditor || cfg.BodyEditor {
+			edited, err := editBody(result, config.GetGitEditor(), f, config.LineEnding(), cfg, os.Stderr)
 			if err != nil {
-				log.Fatalf("unable to create fil %s: %+v", f, err)
+				if draftErr := saveDraft(draftPath(), result); draftErr == nil {
+					fmt.Fprintln(os.Stderr, "saved the message as a draft; run git-cc again to restore it")
+				}
+				log.Fatal(err)
 			}
-			_, err = file.Write([]byte(result))
-			if err != nil {
-				log.Fatalf("unable to write to file %s: %+v", f, err)
+			result = edited
+			for i, param := range commitParams {
+				if param == "--edit" {
+					commitParams[i] = "--no-edit" // the body was just edited
+				}
+			}
+		}
+		if err := config.WriteFileAtomic(f, []byte(result), 0644); err != nil {
+			log.Fatal(err)
+		}
+		commitWithRetries(result, cfg, dryRun, commitParams, push)
+	} else {
+		cc.Footers = cfg.OrderFooters(cfg.NormalizeFooters(
+			parser.MergeFooters(cc.Footers, cfg.FootersFor(cc.Type)),
+		))
+		filtered, err := cfg.FilterDescription(cc.Description)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", filterWarning(err))
+		}
+		cc.Description = config.ApplyCase(cfg.SubjectCase, filtered)
+		if missingRevertRef(cc.Type, cc.Body, cc.Footers) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", config.T(config.WarningRevertRef))
+		}
+		if err := cfg.CheckScopeLength(cc.Scope); err != nil {
+			if cfg.EnforceMaxLength {
+				log.Fatal(err)
+			}
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		if hint := moodHint(cc.Description, cfg); hint != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", hint)
+		}
+		if err := cfg.CheckBodyLength(cc.Body); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		if placeholder := cfg.Placeholder(cc.Description); placeholder != "" {
+			err := placeholderErr(placeholder, cfg.BlockPlaceholderDescriptions)
+			if cfg.BlockPlaceholderDescriptions {
+				log.Fatal(err)
 			}
-			doCommit(result, dryRun, commitParams)
+			fmt.Fprintf(os.Stderr, "warning: %s\n", config.Tf(config.WarningPlaceholder, placeholder))
+		}
+		if cfg.NeedsBreakingChangeDescription(cc.BreakingChange, cc.Footers) {
+			log.Fatal(config.BreakingChangeDescriptionError())
+		}
+		if err := cfg.CheckScopeRequired(cc.Type, cc.Scope); err != nil {
+			log.Fatal(err)
+		}
+		if err := cfg.CheckScopeAllowed(cc.Type, cc.Scope); err != nil {
+			log.Fatal(err)
 		}
+		if missing := cfg.MissingFooters(cc.Type, cc.Footers); len(missing) > 0 {
+			log.Fatal(config.MissingFootersError(cc.Type, missing))
+		}
+		formatted, warning, err := formatWithinLimit(cc, cfg)
+		if err != nil {
+			log.Fatal(err)
+		} else if warning != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+		if err := checkRoundTrip(formatted, *cc, cfg); err != nil {
+			log.Fatal(err)
+		}
+		commitWithRetries(formatted, cfg, dryRun, commitParams, push)
+	}
+}
+
+// interactively edit `cc`, returning the composed message or "" if the user
+// cancelled.
+func runTUI(cc *parser.CC, cfg config.Cfg, options ...tea.ProgramOption) string {
+	choice := make(chan string, 1)
+	m := initialModel(choice, cc, cfg)
+	ui := tea.NewProgram(m, options...)
+	fmt.Print(enableBracketedPaste)
+	err := ui.Start()
+	fmt.Print(disableBracketedPaste)
+	if err != nil {
+		log.Fatal(err)
+	}
+	result := <-choice
+	close(choice)
+	return result
+}
+
+// the message of a --freeform commit: the -m paragraphs, else the args as the
+// subject, else a subject read from `in`. The body can be added in git's
+// editor. Returns "" if there's no subject.
+func freeformMessage(messages []string, args []string, in io.Reader, out io.Writer) string {
+	message := strings.Join(messages, "\n\n")
+	if message == "" {
+		message = strings.Join(args, " ")
+	}
+	if strings.TrimSpace(message) == "" {
+		fmt.Fprint(out, "subject: ")
+		message, _ = bufio.NewReader(in).ReadString('\n')
+	}
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return ""
+	}
+	return message + "\n"
+}
+
+// commit a merge using the message git drafted rather than a conventional one.
+func commitMerge(dryRun bool, commitParams []string) {
+	fmt.Fprintln(os.Stderr, "merge in progress; using git's merge message")
+	commitWithGitsMessage(dryRun, commitParams)
+}
+
+// run `git commit` with `commitParams`, letting git write the message, then
+// exit.
+func commitWithGitsMessage(dryRun bool, commitParams []string) {
+	cmd := append([]string{config.GitCommand, "commit"}, commitParams...)
+	if dryRun {
+		fmt.Printf("would run: `%s`\n", strings.Join(cmd, " "))
+		os.Exit(0)
+	}
+	if err := runCommand(cmd, os.Stdin, os.Stdout, os.Stderr); err != nil {
+		log.Fatalf("failed running `%+v`: %+v", cmd, err)
+	}
+	os.Exit(0)
+}
+
+// the `git commit` parameters for a --fixup or --squash commit, whose message
+// git generates, or nil if neither flag is set. The delegated flags are kept,
+// and git decides whether to open an editor unless --no-edit was passed.
+func fixupParams(cmd *cobra.Command) ([]string, error) {
+	fixup, _ := cmd.Flags().GetString("fixup")
+	squash, _ := cmd.Flags().GetString("squash")
+	if fixup != "" && squash != "" {
+		return nil, errors.New("--fixup and --squash are mutually exclusive")
+	}
+	if message, _ := cmd.Flags().GetStringArray("message"); len(message) > 0 && (fixup != "" || squash != "") {
+		return nil, errors.New("-m can't be combined with --fixup or --squash, since git writes their messages")
+	}
+	var params []string
+	if fixup != "" {
+		params = []string{"--fixup", fixup}
+	} else if squash != "" {
+		params = []string{"--squash", squash}
 	} else {
-		doCommit(cc.ToString(), dryRun, commitParams)
+		return nil, nil
 	}
+	for _, param := range getGitCommitCmd(cmd) {
+		if param != "--edit" && param != "--no-edit" {
+			params = append(params, param)
+		}
+	}
+	if noEdit, _ := cmd.Flags().GetBool("no-edit"); noEdit {
+		params = append(params, "--no-edit")
+	}
+	return params, nil
 }
 
 var Cmd = &cobra.Command{
 	Use:   "git-cc",
 	Short: "write conventional commits",
-	// not using cobra subcommands since they prevent passing arbitrary arguments
+	// arbitrary args are needed to pass partial commits, e.g. `git cc feat: add`;
+	// subcommands like `list` are only matched by their exact first argument.
+	Args: cobra.ArbitraryArgs,
+	CompletionOptions: cobra.CompletionOptions{
+		DisableDefaultCmd: true, // see --generate-shell-completion
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		version, _ := cmd.Flags().GetBool("version")
 		if version {
@@ -150,6 +852,15 @@ var Cmd = &cobra.Command{
 			generateShellCompletion(cmd, args)
 			os.Exit(0)
 		}
+		if printPath, _ := cmd.Flags().GetBool("print-message-path"); printPath {
+			loadConfig(cmd) // for git_command
+			file, err := config.CommitMessageFile()
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(file)
+			os.Exit(0)
+		}
 		genManPage, _ := cmd.Flags().GetBool("generate-man-page")
 		if genManPage {
 			generateManPage(cmd, args)
@@ -164,14 +875,40 @@ func init() {
 	Cmd.Flags().Bool("dry-run", false, "Only print the resulting conventional commit message; don't commit.")
 	Cmd.Flags().StringArrayP("message", "m", []string{}, "pass a complete conventional commit. If valid, it'll be committed without editing.")
 	Cmd.Flags().Bool("version", false, "print the version")
+	Cmd.Flags().Bool("print-message-path", false, "print the path of the file git-cc writes commit messages to")
+	Cmd.Flags().StringP("reuse-message", "C", "", "edit the message of the given commit into a new commit")
+	Cmd.Flags().Bool("resume", false, "edit the message of the last commit git rejected, e.g. by a hook")
+	Cmd.Flags().Bool("skip-round-trip-check", false, "commit even if the message doesn't parse back to the entered type, scope, and description")
+	Cmd.Flags().Bool("diff-stat", false, "append a summary of the staged changes to the body")
+	Cmd.Flags().String("type", "", "use this commit type; exits with suggestions if it isn't configured")
+	Cmd.Flags().Bool("push", false, "push after committing, with push_command if configured")
+	Cmd.Flags().Bool("set-upstream", false, "push after committing with `git push -u origin HEAD`")
+	Cmd.Flags().String("fixup", "", "commit with `git commit --fixup <commit>` instead of prompting, for interactive rebases")
+	Cmd.Flags().String("squash", "", "commit with `git commit --squash <commit>` instead of prompting, for interactive rebases")
+	Cmd.Flags().Bool("freeform", false, "commit a plain subject and body without a type or scope; such commits fail `git cc lint`")
+	Cmd.Flags().Bool("plain", false, "prompt line by line instead of running the full-screen interface; the default without a terminal")
+	Cmd.Flags().Bool("body-editor", false, "after the TUI, write the body and footers in the git editor below the header (default: body_editor)")
+	Cmd.Flags().Bool("protocol", false, "exchange the steps as JSON lines on stdin and stdout, for editor plugins")
+	Cmd.Flags().String("revert", "", "reference the reverted `commit` in a Refs footer, defaulting the type to revert")
+	Cmd.PersistentFlags().Bool("scope-last", false, "write the scope after the description, e.g. \"type: description (scope)\". Such headers don't follow the conventional commits spec.")
+	Cmd.PersistentFlags().Bool("minimal", false, "only prompt for the commit type and description")
+	Cmd.PersistentFlags().Bool("enforce-length", false, "stop typing at header_max_length for this run (default: enforce_header_max_length or $GITCC_ENFORCE_HEADER_MAX_LENGTH)")
+	Cmd.PersistentFlags().Bool("no-enforce-length", false, "allow headers longer than header_max_length for this run")
+	Cmd.PersistentFlags().Bool("config-from-staged", false, "use the config file nearest the staged files rather than the working directory")
+	Cmd.PersistentFlags().StringArray("config-path", []string{}, "search this `dir` for commit_convention.yml before the usual places; repeatable, with earlier dirs taking precedence")
+	Cmd.PersistentFlags().Bool("no-walk", false, "only search the --config-path dirs, not the parent directories or $HOME")
+	Cmd.PersistentFlags().Bool("no-config", false, "use only the built-in defaults, ignoring config files and the environment variables that configure git-cc")
+	Cmd.PersistentFlags().String("profile", "", "merge the named `profile` from the config file's profiles over the base config (default: $GITCC_PROFILE)")
 	// TODO: accept more of git commit's flags; see https://git-scm.com/docs/git-commit
 	// likely: --cleanup=<mode>
 	// more difficult, and possibly better done manually: --amend, -C <commit>
 	// --reuse-message=<commit>, -c <commit>, --reedit-message=<commit>,
 	// --fixup=<commit>, --squash=<commit>
-	Cmd.Flags().String("author", "", "delegated to git-commit")
-	Cmd.Flags().String("date", "", "delegated to git-commit")
+	Cmd.Flags().String("author", "", "override the commit's `author`, e.g. \"Name <email>\"; see the git-commit docs")
+	Cmd.Flags().String("date", "", "override the author `date` in any format git commit accepts, e.g. for imports")
 	Cmd.Flags().BoolP("all", "a", false, "see the git-commit docs for --all|-a")
+	Cmd.Flags().Bool("auto-stage", false, "if nothing is staged, stage all changes with git add -A without asking (see auto_stage_when_empty)")
+	Cmd.Flags().Bool("allow-empty", false, "commit even if nothing is staged, e.g. to trigger CI")
 	Cmd.Flags().BoolP("signoff", "s", false, "see the git-commit docs for --signoff|-s")
 	Cmd.Flags().Bool("no-gpg-sign", false, "see the git-commit docs for --no-gpg-sign")
 	Cmd.Flags().Bool("no-post-rewrite", false, "Bypass the post-rewrite hook")
@@ -0,0 +1,638 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+func TestNormalizingScopeCase(t *testing.T) {
+	cfg := testCfg
+	cfg.ScopeCase = config.CaseLower
+	cc := &parser.CC{Type: "feat", Scope: "CLI", Description: "Add It"}
+	normalizeCase(cc, cfg)
+	if cc.Scope != "cli" {
+		t.Errorf("expected a lower-case scope, got %q", cc.Scope)
+	}
+	if cc.Type != "feat" || cc.Description != "Add It" {
+		t.Errorf("only the scope should change: %+v", cc)
+	}
+	if !cc.ValidScope(cfg.Scopes) {
+		t.Errorf("the normalized scope should match the configured scope")
+	}
+}
+
+func TestPromptingToRetry(t *testing.T) {
+	test := func(answer string, expected bool) func(*testing.T) {
+		return func(t *testing.T) {
+			out := &strings.Builder{}
+			gitErr := errors.New("exit status 1")
+			actual := promptRetry(strings.NewReader(answer), out, "hook rejected\n", gitErr)
+			if actual != expected {
+				t.Errorf("answering %q: expected %v", answer, expected)
+			}
+			if !strings.Contains(out.String(), "hook rejected") {
+				t.Errorf("expected git's stderr to be shown, got %q", out.String())
+			}
+		}
+	}
+	t.Run("retries by default", test("\n", true))
+	t.Run("retries on yes", test("y\n", true))
+	t.Run("aborts on no", test("n\n", false))
+	t.Run("aborts on anything else", test("abort\n", false))
+}
+
+func TestPreservingTheBodyForRetries(t *testing.T) {
+	message := "feat(cli): x\n\nsome body\n\nRefs: #1\n"
+	cc, _ := parser.ParseAsMuchOfCCAsPossible(message)
+	choice := make(chan string, 1)
+	m := initialModel(choice, cc, testCfg)
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != message {
+		t.Errorf("expected %q, got %q", message, result)
+	}
+}
+
+func TestCommittingWithACustomSeparator(t *testing.T) {
+	cfg := testCfg
+	cfg.HeaderSeparator = ":"
+	if cfg.SeparatorWarning() == "" {
+		t.Error("expected a warning about the non-standard separator")
+	}
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Scope: "cli", Description: "x"}, cfg)
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != "feat(cli):x\n" {
+		t.Errorf("expected the custom separator, got %q", result)
+	}
+	cc := seedFromMessage("feat(cli):x\n", cfg.ParserOption())
+	if actual := formatCommit(cc, cfg); actual != "feat(cli):x\n\n" {
+		t.Errorf("expected -m to round-trip the custom separator, got %q", actual)
+	}
+	if cc := seedFromMessage("feat(cli):x\n"); cc.Type != "" {
+		t.Errorf("expected the standard separator without the option, got %+v", cc)
+	}
+	if testCfg.SeparatorWarning() != "" {
+		t.Error("expected no warning about the standard separator")
+	}
+}
+
+func TestCheckingTheHeaderLengthOfMessages(t *testing.T) {
+	cfg := testCfg
+	cfg.HeaderMaxLength = 20 // "feat(cli): " leaves 9 columns
+	cfg.EnforceMaxLength = true
+	cfg.OnMaxLength = config.OnMaxLengthError
+	test := func(cfg config.Cfg, description string, expected string, warns bool, fails bool) func(*testing.T) {
+		return func(t *testing.T) {
+			cc := &parser.CC{Type: "feat", Scope: "cli", Description: description}
+			formatted, warning, err := formatWithinLimit(cc, cfg)
+			if fails {
+				if err == nil || !strings.Contains(err.Error(), "the limit is") {
+					t.Errorf("expected an error naming the limit, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if formatted != expected {
+				t.Errorf("expected %q, got %q", expected, formatted)
+			}
+			if (warning != "") != warns {
+				t.Errorf("unexpected warning %q", warning)
+			}
+		}
+	}
+	t.Run("under the limit", test(cfg, "add x", "feat(cli): add x\n\n", false, false))
+	t.Run("at the limit", test(cfg, "add a bcd", "feat(cli): add a bcd\n\n", false, false))
+	t.Run("over the limit", test(cfg, "add a bcde", "", false, true))
+	unenforced := cfg
+	unenforced.EnforceMaxLength = false
+	t.Run("over an unenforced limit", test(unenforced, "add a bcde", "feat(cli): add a bcde\n\n", true, false))
+	truncating := cfg
+	truncating.OnMaxLength = config.OnMaxLengthTruncate
+	t.Run("truncated", test(truncating, "add a bcde", "feat(cli): add a bcd\n\n", true, false))
+	t.Run("truncated at a space", test(truncating, "add abcd e", "feat(cli): add abcd\n\n", true, false))
+	truncating.HeaderMaxLength = 11
+	t.Run("with no room to truncate", test(truncating, "x", "", false, true))
+}
+
+func TestFilteringTheComposedMessage(t *testing.T) {
+	cfg := testCfg
+	cfg.MessageFilter = "cat"
+	if actual, err := filterMessage("feat(cli): x\n", cfg); err != nil || actual != "feat(cli): x\n" {
+		t.Errorf("expected an identity filter to keep the message, got %q, %v", actual, err)
+	}
+	cfg.MessageFilter = "sed 's/^feat(cli): x$/feat(cli): add x/'"
+	if actual, err := filterMessage("feat(cli): x\n", cfg); err != nil || actual != "feat(cli): add x\n" {
+		t.Errorf("expected the filtered message, got %q, %v", actual, err)
+	}
+	cfg.MessageFilter = "echo 'oops: x'"
+	if _, err := filterMessage("feat(cli): x\n", cfg); err == nil || !strings.Contains(err.Error(), "unknown type 'oops'") {
+		t.Errorf("expected the filtered message to be checked, got %v", err)
+	}
+}
+
+func TestRoundTrippingTheScopeLastLayout(t *testing.T) {
+	cfg := testCfg
+	cfg.ScopePosition = config.ScopeSuffix
+	message := "feat: add x (cli)\n\n"
+	cc, _ := parser.ParseAsMuchOfCCAsPossible(message)
+	readScopeSuffix(cc, cfg)
+	if cc.Scope != "cli" || cc.Description != "add x" {
+		t.Errorf("unexpected parse %+v", cc)
+	}
+	if actual := formatCommit(cc, cfg); actual != message {
+		t.Errorf("expected %q, got %q", message, actual)
+	}
+}
+
+func TestCheckingTheTypeFlag(t *testing.T) {
+	cfg := config.Cfg{CommitTypes: []map[string]string{{"feat": ""}, {"fix": ""}, {"docs": ""}}}
+	t.Run("configured types pass", func(t *testing.T) {
+		if err := checkTypeFlag("fix", cfg); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("near misses get suggestions", func(t *testing.T) {
+		err := checkTypeFlag("fet", cfg)
+		if err == nil || !strings.Contains(err.Error(), "did you mean 'feat'?") {
+			t.Errorf("expected a suggestion of feat, got %v", err)
+		}
+	})
+	t.Run("distant types get a plain error", func(t *testing.T) {
+		err := checkTypeFlag("xyzzy", cfg)
+		if err == nil || err.Error() != "unknown type 'xyzzy'" {
+			t.Errorf("expected a plain error, got %v", err)
+		}
+	})
+}
+
+func TestPreselectingFromTheBranch(t *testing.T) {
+	cfg := testCfg
+	cfg.BranchPattern = config.DefaultBranchPattern
+	cc := &parser.CC{}
+	readBranch(cc, cfg, "feat/cli/x")
+	if cc.Type != "" {
+		t.Errorf("type_from_branch should be opt-in, got %q", cc.Type)
+	}
+	cfg.TypeFromBranch = true
+	readBranch(cc, cfg, "feat/cli/x")
+	if cc.Type != "feat" || cc.Scope != "cli" {
+		t.Errorf("expected feat(cli), got %s(%s)", cc.Type, cc.Scope)
+	}
+	cc = &parser.CC{Type: "fix"}
+	readBranch(cc, cfg, "feat/cli/x")
+	if cc.Type != "fix" || cc.Scope != "" {
+		t.Errorf("a given type should win over the branch, got %s(%s)", cc.Type, cc.Scope)
+	}
+}
+
+func TestFindingTheCommonDirectory(t *testing.T) {
+	for expected, files := range map[string][]string{
+		"packages/api": {"packages/api/go.mod", "packages/api/cmd/main.go"},
+		"packages":     {"packages/api/go.mod", "packages/ui/index.ts"},
+		".":            {"README.md", "packages/api/go.mod"},
+	} {
+		if actual := commonDir(files); actual != expected {
+			t.Errorf("expected %q for %v, got %q", expected, files, actual)
+		}
+	}
+}
+
+func TestFindingTheStagedDirectory(t *testing.T) {
+	git := fakeGit(t)
+	git.outputs["diff --name-only --cached -z"] = "docs/caf\u00e9 menu.md\x00docs/\"quoted\".md\x00"
+	if actual := stagedDir(); actual != filepath.Join(git.dir, "docs") {
+		t.Errorf("expected the unquoted common directory, got %q", actual)
+	}
+}
+
+func TestFindingRevertReferences(t *testing.T) {
+	for _, c := range []struct {
+		commitType string
+		body       string
+		footers    []string
+		missing    bool
+	}{
+		{"revert", "", nil, true},
+		{"revert", "it broke the build", []string{"Reviewed-by: Z"}, true},
+		{"revert", "This reverts commit 0123abc.", nil, false},
+		{"revert", "", []string{"Refs: HEAD~2"}, false},
+		{"feat", "", nil, false},
+	} {
+		if missing := missingRevertRef(c.commitType, c.body, c.footers); missing != c.missing {
+			t.Errorf("expected missingRevertRef(%q, %q, %v) == %v", c.commitType, c.body, c.footers, c.missing)
+		}
+	}
+}
+
+func TestAllowingEmptyCommits(t *testing.T) {
+	if !requiresStagedChanges(Cmd) {
+		t.Error("expected staged changes to be required by default")
+	}
+	if err := Cmd.Flags().Set("allow-empty", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer Cmd.Flags().Set("allow-empty", "false")
+	if requiresStagedChanges(Cmd) {
+		t.Error("expected --allow-empty to skip the staged-changes check")
+	}
+	found := false
+	for _, arg := range getGitCommitCmd(Cmd) {
+		found = found || arg == "--allow-empty"
+	}
+	if !found {
+		t.Errorf("expected --allow-empty to reach git, got %v", getGitCommitCmd(Cmd))
+	}
+}
+
+func TestOverridingLengthEnforcement(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	contents := []byte("enforce_header_max_length: true\n")
+	if err := os.WriteFile(filepath.Join(dir, "commit_convention.yml"), contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+	enforced := func() bool {
+		cfg := loadConfig(Cmd)
+		return initialModel(make(chan string, 1), &parser.CC{}, cfg).descriptionInput.Enforced()
+	}
+	if !enforced() {
+		t.Error("expected the config to enforce the length")
+	}
+	t.Setenv("GITCC_ENFORCE_HEADER_MAX_LENGTH", "false")
+	if enforced() {
+		t.Error("expected the environment to override the config")
+	}
+	if err := Cmd.ParseFlags([]string{"--enforce-length"}); err != nil {
+		t.Fatal(err)
+	}
+	defer Cmd.Flags().Set("enforce-length", "false")
+	if !enforced() {
+		t.Error("expected --enforce-length to override the environment")
+	}
+}
+
+func TestIgnoringTheConfig(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	contents := []byte("commit_types: [custom]\nscopes: [api]\nheader_max_length: 50\n")
+	if err := os.WriteFile(filepath.Join(dir, "commit_convention.yml"), contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.ConfigYAMLEnv, "scopes: [web]\n")
+	t.Setenv("GITCC_ENFORCE_HEADER_MAX_LENGTH", "true")
+	if err := Cmd.ParseFlags([]string{"--no-config"}); err != nil {
+		t.Fatal(err)
+	}
+	defer Cmd.Flags().Set("no-config", "false")
+	cfg := loadConfig(Cmd)
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint(config.AngularPresetCommitTypes) {
+		t.Errorf("expected the preset types, got %v", cfg.CommitTypes)
+	}
+	if len(cfg.Scopes) != 0 || cfg.HeaderMaxLength != 72 || cfg.EnforceMaxLength {
+		t.Errorf("expected only defaults, got %v, %d, and %v", cfg.Scopes, cfg.HeaderMaxLength, cfg.EnforceMaxLength)
+	}
+}
+
+func TestAppendingTheDiffStat(t *testing.T) {
+	stat := " cmd/cli.go | 12 ++++++++++--\n 1 file changed, 10 insertions(+), 2 deletions(-)\n"
+	trimmed := strings.TrimRight(stat, "\n")
+	test := func(body string, stat string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			if actual := appendDiffStat(body, stat); actual != expected {
+				t.Errorf("expected %q, got %q", expected, actual)
+			}
+		}
+	}
+	t.Run("after the body", test("why", stat, "why\n\n"+trimmed))
+	t.Run("as the body", test("", stat, trimmed))
+	t.Run("only once", test("why\n\n"+trimmed, stat, "why\n\n"+trimmed))
+	t.Run("not when there's no stat", test("why", "", "why"))
+}
+
+func TestSeedingFromAnotherCommit(t *testing.T) {
+	test := func(message string, expected parser.CC) func(*testing.T) {
+		return func(t *testing.T) {
+			actual := seedFromMessage(message)
+			if fmt.Sprintf("%+v", *actual) != fmt.Sprintf("%+v", expected) {
+				t.Errorf("expected %+v, got %+v", expected, *actual)
+			}
+		}
+	}
+	t.Run("conventional", test(
+		"feat(cli): add x\n\nbecause y\n\nRefs: #1\n",
+		parser.CC{Type: "feat", Scope: "cli", Description: "add x", Body: "because y", Footers: []string{"Refs: #1"}},
+	))
+	t.Run("non-conventional", test(
+		"Add x to the cli\n\nbecause y\n",
+		parser.CC{Description: "Add x to the cli", Body: "because y"},
+	))
+}
+
+func TestPushingOnlyAfterCommitting(t *testing.T) {
+	git := fakeGit(t)
+	push := pushCommand(testCfg, false)
+	calls := func() string {
+		subcommands := []string{}
+		for _, args := range git.ran {
+			subcommands = append(subcommands, args[0])
+		}
+		git.ran = nil
+		return strings.Join(subcommands, " ")
+	}
+
+	git.failing["commit --message feat: x\n"] = true
+	if _, err := commitAndPush("feat: x\n", false, nil, push); err == nil || errors.Is(err, errPushFailed) {
+		t.Errorf("expected the commit to fail, got %v", err)
+	}
+	if actual := calls(); actual != "rev-parse commit" {
+		t.Errorf("expected no push after a failed commit, got %q", actual)
+	}
+
+	delete(git.failing, "commit --message feat: x\n")
+	if _, err := commitAndPush("feat: x\n", false, nil, push); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if actual := calls(); actual != "rev-parse commit push" {
+		t.Errorf("expected a push after the commit, got %q", actual)
+	}
+
+	cfg := testCfg
+	cfg.PushCommand = "exit 3"
+	_, err := commitAndPush("feat: x\n", false, nil, pushCommand(cfg, false))
+	if !errors.Is(err, errPushFailed) {
+		t.Errorf("expected the push to fail after committing, got %v", err)
+	}
+}
+
+func TestComposingFreeformMessages(t *testing.T) {
+	out := &bytes.Buffer{}
+	if actual := freeformMessage([]string{"Fix the thing", "details"}, nil, strings.NewReader(""), out); actual != "Fix the thing\n\ndetails\n" {
+		t.Errorf("expected the -m paragraphs, got %q", actual)
+	}
+	if actual := freeformMessage(nil, []string{"Fix", "the", "thing"}, strings.NewReader(""), out); actual != "Fix the thing\n" {
+		t.Errorf("expected the args as the subject, got %q", actual)
+	}
+	if out.Len() > 0 {
+		t.Errorf("expected no prompt, got %q", out.String())
+	}
+	if actual := freeformMessage(nil, nil, strings.NewReader("Update deps\n"), out); actual != "Update deps\n" {
+		t.Errorf("expected the prompted subject, got %q", actual)
+	}
+	if actual := freeformMessage(nil, nil, strings.NewReader("\n"), out); actual != "" {
+		t.Errorf("expected no message without a subject, got %q", actual)
+	}
+}
+
+func TestForwardingFixups(t *testing.T) {
+	if params, err := fixupParams(Cmd); params != nil || err != nil {
+		t.Errorf("expected no fixup by default, got %v, %v", params, err)
+	}
+	defer Cmd.Flags().Set("fixup", "")
+	defer Cmd.Flags().Set("all", "false")
+	if err := Cmd.ParseFlags([]string{"--fixup", "abc1234", "--all"}); err != nil {
+		t.Fatal(err)
+	}
+	params, err := fixupParams(Cmd)
+	if err != nil || !strings.HasPrefix(strings.Join(params, " "), "--fixup abc1234 --all") {
+		t.Errorf("expected the fixup and staging flags to reach git, got %v, %v", params, err)
+	}
+	defer Cmd.Flags().Set("squash", "")
+	if err := Cmd.Flags().Set("squash", "abc1234"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fixupParams(Cmd); err == nil {
+		t.Error("expected --fixup and --squash to conflict")
+	}
+	if err := Cmd.Flags().Set("squash", ""); err != nil {
+		t.Fatal(err)
+	}
+	defer Cmd.Flags().Set("no-edit", "false")
+	if err := Cmd.Flags().Set("no-edit", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if params, _ := fixupParams(Cmd); params[len(params)-1] != "--no-edit" {
+		t.Errorf("expected an explicit --no-edit to reach git, got %v", params)
+	}
+	withMessage := &cobra.Command{}
+	withMessage.Flags().String("fixup", "abc1234", "")
+	withMessage.Flags().String("squash", "", "")
+	withMessage.Flags().StringArray("message", []string{"fix: x"}, "")
+	if _, err := fixupParams(withMessage); err == nil {
+		t.Error("expected -m and --fixup to conflict")
+	}
+}
+
+func TestForwardingTheDate(t *testing.T) {
+	git := fakeGit(t)
+	defer Cmd.Flags().Set("date", "")
+	if err := Cmd.ParseFlags([]string{"--date", "2 weeks ago"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doCommit("feat: x\n", false, getGitCommitCmd(Cmd)); err != nil {
+		t.Fatal(err)
+	}
+	if commit := git.last("commit"); !contains(commit, "--date=2 weeks ago") {
+		t.Errorf("expected the date to reach git as one argument, got %q", commit)
+	}
+	if err := Cmd.Flags().Set("date", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range getGitCommitCmd(Cmd) {
+		if strings.HasPrefix(arg, "--date") {
+			t.Errorf("expected no --date without the flag, got %q", arg)
+		}
+	}
+}
+
+func TestCommittingPathspecs(t *testing.T) {
+	git := fakeGit(t)
+	git.failing["ls-files --error-unmatch -- cli.go"] = true // exists on disk
+	git.failing["ls-files --error-unmatch -- missing.go"] = true
+
+	if err := Cmd.ParseFlags([]string{"feat:", "x", "--", "cli.go", "deleted.go"}); err != nil {
+		t.Fatal(err)
+	}
+	args, paths := splitPathspecs(Cmd, Cmd.Flags().Args())
+	if strings.Join(args, " ") != "feat: x" || strings.Join(paths, " ") != "cli.go deleted.go" {
+		t.Errorf("expected the paths after --, got %q and %q", args, paths)
+	}
+	if err := checkPathspecs(paths); err != nil {
+		t.Errorf("expected existing and tracked paths to pass, got %v", err)
+	}
+	if err := checkPathspecs([]string{"missing.go"}); err == nil {
+		t.Error("expected an unknown path to fail")
+	}
+
+	params := withPathspecs([]string{"--no-edit"}, paths)
+	if _, err := doCommit("feat: x\n", false, params); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"commit", "--message", "feat: x\n", "--no-edit", "--", "cli.go", "deleted.go"}
+	if actual := git.last("commit"); strings.Join(actual, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected the paths after the message and flags, got %q", actual)
+	}
+}
+
+// a GitRunner answering from canned output and recording each command's
+// arguments. Commands without output succeed silently unless they're failing.
+type recordingGit struct {
+	dir     string            // the repository root and git directory
+	outputs map[string]string // stdout by space-separated command
+	failing map[string]bool   // the commands that exit non-zero
+	ran     [][]string
+	log     io.Writer // if set, gets each command, one argument per line
+}
+
+// replace the config.Runner with a recordingGit for the rest of the test
+func fakeGit(t *testing.T) *recordingGit {
+	dir := t.TempDir()
+	git := &recordingGit{
+		dir: dir,
+		outputs: map[string]string{
+			"rev-parse --show-toplevel":    dir + "\n",
+			"rev-parse --absolute-git-dir": dir + "\n",
+		},
+		failing: map[string]bool{},
+	}
+	original := config.Runner
+	config.Runner = git
+	t.Cleanup(func() { config.Runner = original })
+	return git
+}
+
+func (git *recordingGit) Output(args ...string) (string, error) {
+	git.ran = append(git.ran, args)
+	if git.log != nil {
+		fmt.Fprintln(git.log, strings.Join(args, "\n"))
+	}
+	command := strings.Join(args, " ")
+	if git.failing[command] {
+		return "", fmt.Errorf("git %s: exit status 1", command)
+	}
+	return git.outputs[command], nil
+}
+
+func (git *recordingGit) Run(stdin io.Reader, stdout, stderr io.Writer, args ...string) error {
+	out, err := git.Output(args...)
+	fmt.Fprint(stdout, out)
+	return err
+}
+
+// the arguments of the last `subcommand` run, or nil if there wasn't one
+func (git *recordingGit) last(subcommand string) []string {
+	for i := len(git.ran) - 1; i >= 0; i-- {
+		if git.ran[i][0] == subcommand {
+			return git.ran[i]
+		}
+	}
+	return nil
+}
+
+func contains(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCommittingThroughTheRunner(t *testing.T) {
+	git := fakeGit(t)
+	if _, err := commitAndPush("feat: x\n", false, []string{"--no-edit"}, pushCommand(testCfg, false)); err != nil {
+		t.Fatal(err)
+	}
+	if commit := git.last("commit"); strings.Join(commit, " ") != "commit --message feat: x\n --no-edit" {
+		t.Errorf("expected the commit to run through the runner, got %q", commit)
+	}
+	if push := git.last("push"); push == nil {
+		t.Errorf("expected the push to run through the runner, ran %q", git.ran)
+	}
+}
+
+func TestStagingAllChanges(t *testing.T) {
+	status := " M cmd/cli.go\n?? notes.txt\n"
+	test := func(status string, ask bool, answer string, expectedErr string, expectAdd bool) func(*testing.T) {
+		return func(t *testing.T) {
+			git := fakeGit(t)
+			git.outputs["status --short --untracked-files=all"] = status
+			out := &bytes.Buffer{}
+			err := stageAllChanges(git, ask, strings.NewReader(answer), out)
+			if (err == nil && expectedErr != "") || (err != nil && err.Error() != expectedErr) {
+				t.Errorf("expected error %q, got %v", expectedErr, err)
+			}
+			added := git.last("add") != nil
+			if added != expectAdd {
+				t.Errorf("expected staging to be %v, ran %q", expectAdd, git.ran)
+			}
+			if status != "" && !strings.Contains(out.String(), "?? notes.txt") {
+				t.Errorf("expected the changes to be listed, got %q", out.String())
+			}
+		}
+	}
+	t.Run("confirmed", test(status, true, "y\n", "", true))
+	t.Run("declined", test(status, true, "n\n", "No files staged", false))
+	t.Run("without asking", test(status, false, "", "", true))
+	t.Run("nothing to stage", test("", false, "", "No files staged, and there are no changes to stage", false))
+}
+
+func TestCommittingTheFixType(t *testing.T) {
+	// mainMode exits once it commits, so it runs in a copy of the test binary
+	if args := os.Getenv("GITCC_TEST_ARGS"); args != "" {
+		git := fakeGit(t)
+		git.outputs["diff --name-only --cached"] = "cli.go\n"
+		git.outputs["diff --name-only --cached -z"] = "cli.go\x00"
+		log, err := os.Create(os.Getenv("GITCC_TEST_LOG"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		git.log = log
+		Cmd.SetArgs(strings.Fields(args))
+		if err := Cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	log := filepath.Join(t.TempDir(), "log")
+	// without a colon, the prompts ask for what didn't parse
+	answers := map[string]string{"fix add a typo": "fix\n\nadd a typo\n\n", "fix: add a typo": ""}
+	for args, answer := range answers {
+		os.Remove(log)
+		process := exec.Command(os.Args[0], "-test.run=^TestCommittingTheFixType$")
+		process.Stdin = strings.NewReader(answer)
+		process.Env = append(
+			os.Environ(), "GITCC_TEST_ARGS="+args, "GITCC_TEST_LOG="+log,
+			config.ConfigYAMLEnv+"=commit_types: [feat, fix]\n",
+		)
+		if output, err := process.CombinedOutput(); err != nil {
+			t.Fatalf("`git cc %s` failed: %v\n%s", args, err, output)
+		}
+		data, _ := os.ReadFile(log)
+		if !strings.Contains(string(data), "commit\n--message\nfix: add a typo\n") {
+			t.Errorf("expected `git cc %s` to commit a fix, got %q", args, data)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+// print the names of `options` one per line, for shell completions
+func printNames(out io.Writer, options []map[string]string) {
+	for _, option := range sortedOptions(options) {
+		fmt.Fprintln(out, option[0])
+	}
+}
+
+// complete the --type flag with the configured commit types and their
+// descriptions
+func completeTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := []string{}
+	for _, option := range sortedOptions(loadConfig(cmd).CommitTypes) {
+		completions = append(completions, option[0]+"\t"+option[1])
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// a hidden command printing the names of the configured `options`
+func completeCmd(name string, options func(config.Cfg) []map[string]string) *cobra.Command {
+	return &cobra.Command{
+		Use:    name,
+		Short:  "print the configured names one per line for shell completions",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			printNames(cmd.OutOrStdout(), options(loadConfig(cmd)))
+		},
+	}
+}
+
+func init() {
+	Cmd.AddCommand(
+		completeCmd("__complete-types", func(cfg config.Cfg) []map[string]string { return cfg.CommitTypes }),
+		completeCmd("__complete-scopes", func(cfg config.Cfg) []map[string]string { return cfg.Scopes }),
+	)
+	Cmd.RegisterFlagCompletionFunc("type", completeTypes)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+func TestCompletingTypesAndScopes(t *testing.T) {
+	t.Setenv(config.ConfigYAMLEnv, "commit_types: [feat, chore]\nscopes: {web: the site, api: the api}\n")
+	test := func(args []string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			out := &bytes.Buffer{}
+			Cmd.SetOut(out)
+			Cmd.SetErr(&bytes.Buffer{}) // cobra's completion debugging
+			Cmd.SetArgs(args)
+			defer Cmd.SetOut(nil)
+			defer Cmd.SetErr(nil)
+			if err := Cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != expected {
+				t.Errorf("expected %q, got %q", expected, out.String())
+			}
+		}
+	}
+	t.Run("types", test([]string{"__complete-types"}, "chore\nfeat\n"))
+	t.Run("scopes", test([]string{"__complete-scopes"}, "api\nweb\n"))
+	t.Run("the --type flag", test([]string{"__complete", "--type", ""}, "chore\nfeat\n:4\n"))
+}
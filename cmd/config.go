@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "inspect git-cc's configuration",
+}
+
+var validateConfigCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "check a commit_convention.yml against git-cc's JSON schema",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var path string
+		if len(args) == 1 {
+			path = args[0]
+		} else {
+			store := config.Init()
+			if err := store.ReadInConfig(); err != nil {
+				log.Fatalf("no config file found: %v", err)
+			}
+			path = store.ConfigFileUsed()
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var document interface{}
+		if err = yaml.Unmarshal(data, &document); err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		if document == nil { // an empty file means all defaults
+			document = map[string]interface{}{}
+		}
+		errs := config.ValidateAgainstSchema(document)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%s: ok\n", path)
+	},
+}
+
+var printSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "print the JSON schema for commit_convention.yml",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Stdout.Write(config.Schema)
+	},
+}
+
+// the default location of a new config file
+const newCfgFile = "commit_convention.yml"
+
+var initConfigCmd = &cobra.Command{
+	Use:   "init [path]",
+	Short: "write an example commit_convention.yml, if it doesn't exist",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := newCfgFile
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if err := config.CreateCfgFile(path, config.ExampleCfgFile+"\n"); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "wrote %s\n", path)
+	},
+}
+
+var editConfigCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "open the commit_convention.yml in use in $EDITOR",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := config.Init()
+		store.ReadInConfig()
+		if create, _ := cmd.Flags().GetBool("create"); create && store.ConfigFileUsed() == "" {
+			if err := config.CreateCfgFile(newCfgFile, config.ExampleCfgFile+"\n"); err != nil {
+				log.Fatal(err)
+			}
+			store.SetConfigFile(newCfgFile)
+		}
+		if _, err := config.EditCfgFile(store); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var changelogConfigCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "print a standard-version config with the changelog_sections, e.g. for .versionrc.json",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := loadConfig(cmd).VersionRC()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	},
+}
+
+func init() {
+	editConfigCmd.Flags().Bool("create", false, "write an example config file in the current directory if none is found")
+	configCmd.AddCommand(validateConfigCmd, printSchemaCmd, initConfigCmd, editConfigCmd, changelogConfigCmd)
+	Cmd.AddCommand(configCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+// how long the draft of a failed commit is offered for
+const draftMaxAge = 24 * time.Hour
+
+// the path of the repository's draft, or "" outside a repository
+func draftPath() string {
+	path, err := config.DraftFile()
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// keep `message` at `path` to restore after a failed commit
+func saveDraft(path string, message string) error {
+	if path == "" {
+		return errors.New("not in a git repository")
+	}
+	return config.WriteFileAtomic(path, []byte(message), 0644)
+}
+
+// the draft at `path`, or "" if there's none. Drafts older than draftMaxAge
+// are stale, so they're removed instead.
+func loadDraft(path string, now time.Time) string {
+	if path == "" {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if now.Sub(info.ModTime()) > draftMaxAge {
+		clearDraft(path)
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func clearDraft(path string) {
+	if path != "" {
+		os.Remove(path)
+	}
+}
+
+// the draft to start from: with `resume`, any draft; else, if `ask`, a draft
+// the user chooses to restore. A declined draft is discarded.
+func restoreDraft(path string, resume bool, ask bool, in io.Reader, out io.Writer) string {
+	draft := loadDraft(path, time.Now())
+	switch {
+	case draft == "":
+		if resume {
+			fmt.Fprintln(out, "warning: no draft of a failed commit to resume")
+		}
+		return ""
+	case resume:
+		return draft
+	case !ask:
+		return ""
+	case confirm(in, out, "restore the message of your last failed commit?"):
+		return draft
+	default:
+		clearDraft(path)
+		return ""
+	}
+}
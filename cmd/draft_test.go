@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestoringDrafts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GITCC_DRAFT")
+	message := "feat(cli): add a flag\n\nwith a body\n\nRefs: #1\n"
+	if draft := restoreDraft(path, true, true, strings.NewReader(""), &strings.Builder{}); draft != "" {
+		t.Errorf("expected no draft before one is saved, got %q", draft)
+	}
+	if err := saveDraft(path, message); err != nil {
+		t.Fatal(err)
+	}
+	if draft := loadDraft(path, time.Now()); draft != message {
+		t.Errorf("expected the saved message, got %q", draft)
+	}
+	if cc := seedFromMessage(loadDraft(path, time.Now())); cc.Scope != "cli" || cc.Body != "with a body" {
+		t.Errorf("expected the draft's fields, got %+v", cc)
+	}
+	if draft := restoreDraft(path, false, false, strings.NewReader(""), &strings.Builder{}); draft != "" {
+		t.Errorf("expected no draft without asking or --resume, got %q", draft)
+	}
+	if draft := restoreDraft(path, true, false, strings.NewReader(""), &strings.Builder{}); draft != message {
+		t.Errorf("expected --resume to restore the draft, got %q", draft)
+	}
+	if draft := restoreDraft(path, false, true, strings.NewReader("\n"), &strings.Builder{}); draft != message {
+		t.Errorf("expected restoring by default when asked, got %q", draft)
+	}
+	if draft := restoreDraft(path, false, true, strings.NewReader("n\n"), &strings.Builder{}); draft != "" {
+		t.Errorf("expected declining to skip the draft, got %q", draft)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected a declined draft to be removed, got %v", err)
+	}
+}
+
+func TestDiscardingStaleDrafts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GITCC_DRAFT")
+	if err := saveDraft(path, "feat: x\n"); err != nil {
+		t.Fatal(err)
+	}
+	if draft := loadDraft(path, time.Now().Add(draftMaxAge+time.Minute)); draft != "" {
+		t.Errorf("expected a stale draft to be ignored, got %q", draft)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected a stale draft to be removed, got %v", err)
+	}
+	if err := saveDraft("", "feat: x\n"); err == nil {
+		t.Error("expected saving outside a repository to fail")
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/lint"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// apply the safe, lossless fixes to `message`, returning the fixed message and
+// a description of each fix. Only the header and the first line of each
+// footer are changed; the body and any git comments are left as they are.
+// Fixing a fixed message changes nothing.
+func fixMessage(message string, cfg config.Cfg) (string, []string) {
+	fixes := []string{}
+	lines := strings.Split(message, "\n")
+	if cc, err := parser.ParseHeader(lines[0], cfg.ParserOption()); err == nil {
+		lower := strings.ToLower(cc.Type)
+		fixed := parser.CC{Type: lower}
+		if lower != cc.Type && strings.HasPrefix(lines[0], cc.Type) && fixed.ValidCommitType(cfg.CommitTypes) {
+			lines[0] = lower + strings.TrimPrefix(lines[0], cc.Type)
+			fixes = append(fixes, fmt.Sprintf("lower-cased the type '%s'", cc.Type))
+		}
+		// an ellipsis or a description that's only a period is left alone
+		description := strings.TrimSpace(cc.Description)
+		if strings.HasSuffix(lines[0], ".") && strings.HasSuffix(description, ".") &&
+			!strings.HasSuffix(description, "..") && description != "." {
+			lines[0] = strings.TrimSuffix(lines[0], ".")
+			fixes = append(fixes, "removed the description's trailing period")
+		}
+	}
+	if cfg.FooterValues != config.FooterValuesVerbatim {
+		isComment := func(line string) bool { return strings.HasPrefix(line, "#") }
+		footerStart := len(lines)
+		for i := 2; i < len(lines) && !isComment(lines[i]); i++ {
+			if strings.TrimSpace(lines[i-1]) == "" && parser.FooterTokenOf(lines[i]) != "" {
+				footerStart = i
+				break
+			}
+		}
+		for i := footerStart; i < len(lines) && !isComment(lines[i]); i++ {
+			// continuation lines of multi-line footers are left alone
+			if parser.FooterTokenOf(lines[i]) == "" {
+				continue
+			}
+			if trimmed := parser.TrimFooterValue(lines[i]); trimmed != lines[i] {
+				lines[i] = trimmed
+				fixes = append(fixes, fmt.Sprintf("trimmed the spacing of '%s'", trimmed))
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), fixes
+}
+
+var fixCmd = &cobra.Command{
+	// not `fix`, which would shadow the fix type in e.g. `git cc fix a typo`
+	Use:   "fix-message <file|->",
+	Short: "fix minor issues in a commit message file in place and report the rest; usable as a commit-msg hook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+		message := readMessage(args)
+		fixed, fixes := fixMessage(message, cfg)
+		for _, fix := range fixes {
+			fmt.Fprintf(os.Stderr, "fixed: %s\n", fix)
+		}
+		if args[0] == "-" {
+			fmt.Print(fixed)
+		} else if fixed != message {
+			if err := config.WriteFileAtomic(args[0], []byte(fixed), 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// what's left is for a human to decide, so it doesn't fail the hook
+		lint.Report(os.Stderr, lint.Lint(fixed, cfg))
+	},
+}
+
+func init() {
+	Cmd.AddCommand(fixCmd)
+}
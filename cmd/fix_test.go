@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+func TestFixingMessages(t *testing.T) {
+	cfg := testCfg
+	cfg.FooterValues = config.FooterValuesTrim
+	cases := []struct {
+		message  string
+		expected string
+		fixes    int
+	}{
+		{"feat: add a flag\n", "feat: add a flag\n", 0},
+		{"Feat(cli): add a flag.\n", "feat(cli): add a flag\n", 2},
+		{"FEAT!: add a flag\n\nbody text.  \n\nRefs:   #1  \nCo-authored-by: x\n", "feat!: add a flag\n\nbody text.  \n\nRefs: #1\nCo-authored-by: x\n", 2},
+		// ambiguous or unknown: left alone
+		{"Docs: wait for it...\n", "Docs: wait for it...\n", 0},
+		{"not a conventional header.\n", "not a conventional header.\n", 0},
+		{"feat: add a flag\n\n# Refs:   #1\n", "feat: add a flag\n\n# Refs:   #1\n", 0},
+	}
+	for _, c := range cases {
+		fixed, fixes := fixMessage(c.message, cfg)
+		if fixed != c.expected || len(fixes) != c.fixes {
+			t.Errorf("%q: expected %q with %d fixes, got %q with %v", c.message, c.expected, c.fixes, fixed, fixes)
+		}
+		if again, fixes := fixMessage(fixed, cfg); again != fixed || len(fixes) != 0 {
+			t.Errorf("%q: expected fixing twice to change nothing, got %q (%s)", c.message, again, strings.Join(fixes, "; "))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// the commit sources git passes to prepare-commit-msg for messages that git or
+// the user already wrote; see githooks(5). git-cc leaves these alone so it
+// doesn't mangle e.g. `Merge branch ...` messages.
+var passThroughSources = map[string]bool{
+	"merge":   true,
+	"squash":  true,
+	"commit":  true,
+	"message": true,
+}
+
+// the line above the diff git adds to verbose commit messages; git drops
+// everything from here on when committing.
+const scissors = "# ------------------------ >8 ------------------------"
+
+// split off the diff that git appends below a scissors line when committing
+// with commit.verbose or -v. git writes the diff before running the hook and
+// doesn't regenerate it, so it has to be written back for the editor to show.
+func splitVerboseDiff(content string) (message string, diff string) {
+	if strings.HasPrefix(content, scissors+"\n") {
+		return "", content
+	}
+	if i := strings.Index(content, "\n"+scissors+"\n"); i >= 0 {
+		return content[:i+1], content[i+1:]
+	}
+	return content, ""
+}
+
+// rewrite the message file at `file` with what `compose` makes of its draft,
+// keeping git's comments and any verbose diff. Returns false if `compose`
+// returns "", which aborts the commit.
+func rewriteMessageFile(file string, cfg config.Cfg, compose func(*parser.CC, config.Cfg) string) (bool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	message, diff := string(data), ""
+	// `git commit -v` adds the diff without setting commit.verbose, so look
+	// for the scissors line either way
+	if config.VerboseCommits() || strings.Contains(message, scissors) {
+		message, diff = splitVerboseDiff(message)
+	}
+	draft, comments := splitComments(message)
+	cc, _ := parser.ParseWithFooterMarker(checkDraft(draft, os.Stderr), cfg.FooterMarker, cfg.ParserOption())
+	readScopeSuffix(cc, cfg)
+	readBranch(cc, cfg, config.CurrentBranch())
+	normalizeCase(cc, cfg)
+	result := compose(cc, cfg)
+	if result == "" {
+		return false, nil
+	}
+	if result, err = filterMessage(result, cfg); err != nil {
+		return false, err
+	}
+	return true, config.WriteFileAtomic(file, []byte(result+comments+diff), 0644)
+}
+
+// separate git's `#`-prefixed comment lines from the rest of a message file
+func splitComments(content string) (message string, comments string) {
+	messageLines, commentLines := []string{}, []string{}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			commentLines = append(commentLines, line)
+		} else {
+			messageLines = append(messageLines, line)
+		}
+	}
+	return strings.Join(messageLines, ""), strings.Join(commentLines, "")
+}
+
+// returns `draft`, or "" with a warning if it's invalid UTF-8 or binary, e.g.
+// from a corrupt message file.
+func checkDraft(draft string, warnings io.Writer) string {
+	if utf8.ValidString(draft) && !strings.ContainsRune(draft, 0) {
+		return draft
+	}
+	fmt.Fprintln(warnings, "warning: ignoring the draft message since it isn't valid UTF-8 text")
+	return ""
+}
+
+var prepareCommitMsgCmd = &cobra.Command{
+	Use:   "prepare-commit-msg <file> [<source> [<sha>]]",
+	Short: "compose the commit message from a prepare-commit-msg git hook",
+	Long: `compose the commit message from a prepare-commit-msg git hook, e.g.
+
+    echo 'exec git-cc prepare-commit-msg "$@"' > .git/hooks/prepare-commit-msg
+
+Messages from merges, squashes, -m, and -c/-C are left unchanged.`,
+	Args: cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 && passThroughSources[args[1]] {
+			return
+		}
+		written, err := rewriteMessageFile(args[0], loadConfig(cmd), func(cc *parser.CC, cfg config.Cfg) string {
+			// git hooks don't receive the terminal as stdin
+			return runTUI(cc, cfg, tea.WithInputTTY())
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !written {
+			os.Exit(1) // aborts the commit
+		}
+	},
+}
+
+func init() {
+	Cmd.AddCommand(prepareCommitMsgCmd)
+}
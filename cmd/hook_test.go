@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+func TestLeavingGitGeneratedMessagesAlone(t *testing.T) {
+	for source := range passThroughSources {
+		t.Run(source, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+			original := "Merge branch 'topic'\n# Conflicts:\n"
+			if err := os.WriteFile(file, []byte(original), 0644); err != nil {
+				t.Fatal(err)
+			}
+			prepareCommitMsgCmd.Run(prepareCommitMsgCmd, []string{file, source})
+			data, _ := os.ReadFile(file)
+			if string(data) != original {
+				t.Errorf("expected the message to be unchanged, got %q", string(data))
+			}
+		})
+	}
+}
+
+func TestSplittingComments(t *testing.T) {
+	message, comments := splitComments("feat: x\n\n# Please enter the commit message\n#\n")
+	if message != "feat: x\n\n" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if comments != "# Please enter the commit message\n#\n" {
+		t.Errorf("unexpected comments %q", comments)
+	}
+}
+
+func TestIgnoringCorruptDrafts(t *testing.T) {
+	for _, draft := range []string{"feat: \xff\xfe x\n", "feat: x\x00\x01\n"} {
+		warnings := bytes.Buffer{}
+		if result := checkDraft(draft, &warnings); result != "" {
+			t.Errorf("expected %q to be ignored, got %q", draft, result)
+		}
+		if warnings.Len() == 0 {
+			t.Errorf("expected a warning about %q", draft)
+		}
+	}
+	warnings := bytes.Buffer{}
+	if result := checkDraft("feat: ok ✓\n", &warnings); result != "feat: ok ✓\n" || warnings.Len() > 0 {
+		t.Errorf("valid drafts should be kept, got %q", result)
+	}
+}
+
+const verboseDiff = scissors + "\n# Do not modify or remove the line above.\n" +
+	"diff --git a/x b/x\n+x\n"
+
+func TestSplittingVerboseDiffs(t *testing.T) {
+	buffer := "feat: x\n\n# Please enter the commit message\n" + verboseDiff
+	if message, diff := splitVerboseDiff(buffer); message != "feat: x\n\n# Please enter the commit message\n" || diff != verboseDiff {
+		t.Errorf("expected the diff to be split off, got %q and %q", message, diff)
+	}
+	if message, diff := splitVerboseDiff(verboseDiff); message != "" || diff != verboseDiff {
+		t.Errorf("expected only the diff, got %q and %q", message, diff)
+	}
+	if message, diff := splitVerboseDiff("feat: x\n"); message != "feat: x\n" || diff != "" {
+		t.Errorf("expected messages without a diff to be unchanged, got %q and %q", message, diff)
+	}
+}
+
+func TestKeepingTheVerboseDiffInTheMessageFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	buffer := "feat: x\n\n# Please enter the commit message\n" + verboseDiff
+	if err := os.WriteFile(file, []byte(buffer), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var draft *parser.CC
+	written, err := rewriteMessageFile(file, testCfg, func(cc *parser.CC, cfg config.Cfg) string {
+		draft = cc
+		return "feat(cli): y\n"
+	})
+	if !written || err != nil {
+		t.Fatalf("expected the file to be written, got %v", err)
+	}
+	if draft.Description != "x" || len(draft.Footers) > 0 || draft.Body != "" {
+		t.Errorf("expected only the message above the scissors to be parsed, got %+v", draft)
+	}
+	expected := "feat(cli): y\n# Please enter the commit message\n" + verboseDiff
+	if data, _ := os.ReadFile(file); string(data) != expected {
+		t.Errorf("expected the diff to be written back, got %q", data)
+	}
+}
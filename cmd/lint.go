@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/lint"
+)
+
+// read the message to lint from a file path, or stdin if the path is "-"
+func readMessage(args []string) string {
+	var data []byte
+	var err error
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(data)
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file|-]",
+	Short: "check a commit message against the conventions; usable as a commit-msg hook",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+		violations := lint.Lint(readMessage(args), cfg)
+		if strict, _ := cmd.Flags().GetBool("strict"); strict {
+			violations = lint.Strict(violations)
+		}
+		lint.Report(os.Stderr, violations)
+		if lint.Failed(violations) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	lintCmd.Flags().Bool("strict", false, "fail on warnings as well as errors")
+	Cmd.AddCommand(lintCmd)
+}
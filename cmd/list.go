@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+// flatten a list of {name: description} options into sorted name/description
+// pairs so output doesn't depend on map iteration order.
+func sortedOptions(options []map[string]string) [][2]string {
+	result := [][2]string{}
+	for _, option := range options {
+		for name, description := range option {
+			result = append(result, [2]string{name, description})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+	return result
+}
+
+// print the configured commit types and/or scopes. Plain output is one
+// tab-separated `kind name description` line per option.
+func listOptions(out io.Writer, cfg config.Cfg, types bool, scopes bool, plain bool) {
+	sections := []struct {
+		kind    string
+		title   string
+		options []map[string]string
+	}{
+		{"type", "commit types:", cfg.CommitTypes},
+		{"scope", "scopes:", cfg.Scopes},
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for i, section := range sections {
+		if (section.kind == "type" && !types) || (section.kind == "scope" && !scopes) {
+			continue
+		}
+		if plain {
+			for _, opt := range sortedOptions(section.options) {
+				fmt.Fprintf(out, "%s\t%s\t%s\n", section.kind, opt[0], opt[1])
+			}
+			continue
+		}
+		if i > 0 && types {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, section.title)
+		for _, opt := range sortedOptions(section.options) {
+			fmt.Fprintf(w, "  %s\t%s\n", opt[0], opt[1])
+		}
+	}
+	w.Flush()
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "print the configured commit types and scopes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		typesOnly, _ := cmd.Flags().GetBool("types-only")
+		scopesOnly, _ := cmd.Flags().GetBool("scopes-only")
+		plain, _ := cmd.Flags().GetBool("plain")
+		cfg := loadConfig(cmd)
+		listOptions(os.Stdout, cfg, !scopesOnly, !typesOnly, plain)
+	},
+}
+
+func init() {
+	listCmd.Flags().Bool("types-only", false, "only print commit types")
+	listCmd.Flags().Bool("scopes-only", false, "only print scopes")
+	listCmd.Flags().Bool("plain", false, "print tab-separated kind, name, and description lines for use in scripts")
+	listCmd.MarkFlagsMutuallyExclusive("types-only", "scopes-only")
+	Cmd.AddCommand(listCmd)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// the JSON form of a parse error
+type parseErrorJSON struct {
+	Message string `json:"message"`
+	Offset  int    `json:"offset"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+// the JSON form of a parsed message
+type parsedJSON struct {
+	Type           string          `json:"type"`
+	Scope          string          `json:"scope"`
+	Description    string          `json:"description"`
+	Body           string          `json:"body"`
+	Footers        []string        `json:"footers"`
+	Trailing       string          `json:"trailing"`
+	BreakingChange bool            `json:"breaking_change"`
+	Bang           bool            `json:"bang"`
+	Error          *parseErrorJSON `json:"error"`
+}
+
+func toParsedJSON(cc parser.CC, err error) parsedJSON {
+	result := parsedJSON{
+		Type:           cc.Type,
+		Scope:          cc.Scope,
+		Description:    cc.Description,
+		Body:           cc.Body,
+		Footers:        cc.Footers,
+		Trailing:       cc.Trailing,
+		BreakingChange: cc.BreakingChange,
+		Bang:           cc.Bang,
+	}
+	if result.Footers == nil {
+		result.Footers = []string{}
+	}
+	var parseErr *parser.ParseError
+	if errors.As(err, &parseErr) {
+		result.Error = &parseErrorJSON{
+			parseErr.Err.Error(), parseErr.Offset, parseErr.Line, parseErr.Column,
+		}
+	} else if err != nil {
+		result.Error = &parseErrorJSON{Message: err.Error()}
+	}
+	return result
+}
+
+// print each field of `cc` and any parse error on its own line
+func printParsed(out io.Writer, cc parser.CC, err error) {
+	fmt.Fprintf(out, "type:        %s\n", cc.Type)
+	fmt.Fprintf(out, "scope:       %s\n", cc.Scope)
+	fmt.Fprintf(out, "description: %s\n", cc.Description)
+	fmt.Fprintf(out, "breaking:    %v\n", cc.BreakingChange)
+	fmt.Fprintf(out, "bang:        %v\n", cc.Bang)
+	if cc.Body != "" {
+		fmt.Fprintf(out, "body:\n    %s\n", strings.ReplaceAll(cc.Body, "\n", "\n    "))
+	}
+	for i, footer := range cc.Footers {
+		fmt.Fprintf(out, "footer %d:    %s\n", i+1, footer)
+	}
+	if cc.Trailing != "" {
+		fmt.Fprintf(out, "trailing:\n    %s\n", strings.ReplaceAll(cc.Trailing, "\n", "\n    "))
+	}
+	if err != nil {
+		fmt.Fprintf(out, "error:       %v\n", err)
+	}
+}
+
+// parse `message` the way `git cc lint` and the hook do, with the configured
+// header_separator, footer_marker, and scope_position
+func parseMessage(message string, cfg config.Cfg) (parser.CC, error) {
+	cc, err := parser.ParseWithFooterMarker(message, cfg.FooterMarker, cfg.ParserOption())
+	if err != nil {
+		// Parse locates the error in the message
+		if _, located := parser.Parse(message, cfg.ParserOption()); located != nil {
+			err = located
+		}
+	}
+	readScopeSuffix(cc, cfg)
+	return *cc, err
+}
+
+var parseCmd = &cobra.Command{
+	Use:   "parse [file|-]",
+	Short: "print how git-cc parses a commit message, for debugging",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cc, err := parseMessage(readMessage(args), loadConfig(cmd))
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if encodingErr := encoder.Encode(toParsedJSON(cc, err)); encodingErr != nil {
+				log.Fatal(encodingErr)
+			}
+		} else {
+			printParsed(os.Stdout, cc, err)
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	parseCmd.Flags().Bool("json", false, "print the result as JSON")
+	Cmd.AddCommand(parseCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+func TestPrintingParseResults(t *testing.T) {
+	cc, err := parser.Parse("feat(cli)!: add x\n\nmore\n\nRefs: #1\n")
+	out := &bytes.Buffer{}
+	printParsed(out, cc, err)
+	for _, expected := range []string{"type:        feat\n", "scope:       cli\n", "breaking:    true\n", "footer 1:    Refs: #1\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in %q", expected, out.String())
+		}
+	}
+	cc, err = parser.Parse("feat(cli: x")
+	data, _ := json.Marshal(toParsedJSON(cc, err))
+	var decoded parsedJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Error == nil || decoded.Error.Offset != 4 || decoded.Error.Column != 5 {
+		t.Errorf("expected the error's position in the JSON, got %s", data)
+	}
+}
+
+func TestParsingWithTheConfig(t *testing.T) {
+	cfg := testCfg
+	cfg.HeaderSeparator = " - "
+	cfg.FooterMarker = "---"
+	cc, err := parseMessage("feat(cli) - add x\n\nRefs: #1 in the body\n---\nRefs: #2\n", cfg)
+	if err != nil || cc.Scope != "cli" || cc.Description != "add x" || len(cc.Footers) != 1 {
+		t.Errorf("expected the separator and footer marker to be used, got %+v, %v", cc, err)
+	}
+	var parseErr *parser.ParseError
+	if _, err := parseMessage("feat(cli: add x\n", cfg); !errors.As(err, &parseErr) || parseErr.Offset != 4 {
+		t.Errorf("expected the error's position, got %v", err)
+	}
+	cfg.HeaderSeparator = ""
+	cfg.ScopePosition = config.ScopeSuffix
+	if cc, _ := parseMessage("feat: add x (cli)\n", cfg); cc.Scope != "cli" || cc.Description != "add x" {
+		t.Errorf("expected the scope suffix to be read, got %+v", cc)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// terminal escape sequences toggling bracketed paste mode, in which pasted
+// text arrives wrapped in `ESC[200~` ... `ESC[201~`.
+const (
+	enableBracketedPaste  = "\x1b[?2004h"
+	disableBracketedPaste = "\x1b[?2004l"
+)
+
+// bubbletea doesn't recognize the paste markers, so it delivers each as an
+// alt-modified key with the marker's runes following the escape.
+const (
+	pasteStart = "[200~"
+	pasteEnd   = "[201~"
+)
+
+// accumulates bracketed-paste input, which can arrive split across several
+// key messages.
+type pasteBuffer struct {
+	active bool
+	text   []rune
+}
+
+// the runes a key message would have typed while pasting
+func pastedRunes(msg tea.KeyMsg) []rune {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		return msg.Runes
+	case tea.KeyEnter, tea.KeyCtrlJ:
+		return []rune{'\n'}
+	case tea.KeyTab:
+		return []rune{'\t'}
+	default: // drop other control keys rather than injecting them
+		return nil
+	}
+}
+
+// feed a key message to the buffer. Returns whether the message was part of a
+// paste and, once the paste ends, the pasted text.
+func (p pasteBuffer) feed(msg tea.KeyMsg) (next pasteBuffer, consumed bool, pasted string, done bool) {
+	runes := string(msg.Runes)
+	if !p.active {
+		if msg.Type != tea.KeyRunes || !msg.Alt || !strings.HasPrefix(runes, pasteStart) {
+			return p, false, "", false
+		}
+		p = pasteBuffer{active: true}
+		runes = strings.TrimPrefix(runes, pasteStart)
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(runes)}
+	}
+	if msg.Type == tea.KeyRunes && msg.Alt && strings.HasPrefix(runes, pasteEnd) {
+		return pasteBuffer{}, true, cleanPaste(string(p.text)), true
+	}
+	p.text = append(append([]rune{}, p.text...), pastedRunes(msg)...)
+	return p, true, "", false
+}
+
+// normalize line endings and strip control characters other than newlines
+func cleanPaste(text string) string {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || !unicode.IsControl(r) {
+			return r
+		}
+		if r == '\t' {
+			return ' '
+		}
+		return -1
+	}, text)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// the key messages bubbletea decodes from the bytes
+// "\x1b[200~fix the parser\r\nso it works\x1b[201~", with the paste split
+// across two reads of the terminal.
+var splitPaste = []tea.KeyMsg{
+	{Type: tea.KeyRunes, Alt: true, Runes: []rune("[200~fix the par")},
+	{Type: tea.KeyRunes, Runes: []rune("s")},
+	{Type: tea.KeyRunes, Runes: []rune("er")},
+	{Type: tea.KeyEnter},
+	{Type: tea.KeyCtrlJ},
+	{Type: tea.KeyRunes, Runes: []rune("so")},
+	{Type: tea.KeySpace, Runes: []rune(" ")},
+	{Type: tea.KeyEsc}, // a stray control sequence
+	{Type: tea.KeyRunes, Runes: []rune("it works")},
+	{Type: tea.KeyRunes, Alt: true, Runes: []rune("[201~")},
+}
+
+func TestCoalescingBracketedPastes(t *testing.T) {
+	buffer := pasteBuffer{}
+	var consumed, done bool
+	var pasted string
+	for i, msg := range splitPaste {
+		buffer, consumed, pasted, done = buffer.feed(msg)
+		if !consumed {
+			t.Fatalf("message %d should be part of the paste", i)
+		}
+		if done != (i == len(splitPaste)-1) {
+			t.Fatalf("the paste should only end at the end marker (%d)", i)
+		}
+	}
+	if pasted != "fix the parser\n\nso it works" {
+		t.Errorf("unexpected paste %q", pasted)
+	}
+	_, consumed, _, _ = buffer.feed(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if consumed {
+		t.Error("typing after a paste shouldn't be treated as pasting")
+	}
+}
+
+func TestPastingMultipleLinesIntoTheDescription(t *testing.T) {
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Scope: "cli"}, testCfg)
+	for _, msg := range splitPaste {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	if m.viewing != shortDescriptionIndex {
+		t.Fatalf("pasted newlines shouldn't submit the description")
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter)
+	expected := "feat(cli): fix the parser\n\nso it works\n"
+	if result := <-choice; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestWarningAboutBodiesOverTheBudget(t *testing.T) {
+	cfg := testCfg
+	cfg.BodyMaxLength = 5
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Scope: "cli"}, cfg)
+	m, _ = m.paste("fix the parser\nso it works")
+	if m.body != "so it works" {
+		t.Errorf("expected the whole body to be kept, got %q", m.body)
+	}
+	if remaining, limited := m.bodyBudget(); !limited || remaining != -6 {
+		t.Errorf("expected to be 6 characters over, got %d", remaining)
+	}
+	if !strings.Contains(m.View(), "6 character(s) over the limit") {
+		t.Errorf("expected the view to show the overage, got:\n%s", m.View())
+	}
+	m = press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if len(choice) != 0 || !strings.Contains(m.View(), "the body is 11 characters long; the limit is 5") {
+		t.Fatalf("expected a warning about the body, got:\n%s", m.View())
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != "feat(cli): fix the parser\n\nso it works\n" {
+		t.Errorf("expected to commit anyway, got %q", result)
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// whether `f` is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// whether the full-screen TUI can't run, e.g. in a CI shell that pipes stdin
+// and stdout.
+func needsPlainPrompts() bool {
+	return !isTerminal(os.Stdin) || !isTerminal(os.Stdout)
+}
+
+// print each {name: description} option on its own line
+func printOptions(out io.Writer, options []map[string]string) {
+	for _, option := range options {
+		for name, description := range option {
+			fmt.Fprintf(out, "  %s: %s\n", name, description)
+		}
+	}
+}
+
+// record the answer to a step's prompt, validating it the way the TUI would.
+func (m model) answer(step componentIndex, text string) (model, error) {
+	switch step {
+	case commitTypeIndex:
+		text = m.cfg.NormalizeType(text)
+		if err := m.cfg.CheckTypeCase(text); err != nil {
+			return m, err
+		}
+		if !m.typeInput.ShouldSkip(text) {
+			return m, config.Error(config.ErrorUnknownType, text)
+		}
+	case scopeIndex:
+		text = config.ApplyScopeCase(m.cfg.ScopeCase, text)
+		if err := m.cfg.CheckScopeAllowed(m.commit[commitTypeIndex], text); err != nil {
+			return m, err
+		}
+		if text != "" && !m.scopeInput.ShouldSkip(text) {
+			return m, config.Error(config.ErrorUnknownScope, text)
+		}
+		if err := m.cfg.CheckScopeLength(text); err != nil && m.cfg.EnforceMaxLength {
+			return m, err
+		}
+		if err := m.cfg.CheckScopeRequired(m.commit[commitTypeIndex], text); err != nil {
+			return m, err
+		}
+	case shortDescriptionIndex:
+		if text == "" {
+			return m, errors.New(config.T(config.ErrorRequired))
+		}
+		// a failing filter keeps the description as typed
+		text, m.filterErr = m.cfg.FilterDescription(text)
+		text = config.ApplyCase(m.cfg.SubjectCase, text)
+		if placeholder := m.cfg.Placeholder(text); placeholder != "" && m.cfg.BlockPlaceholderDescriptions {
+			return m, placeholderErr(placeholder, true)
+		}
+		if err := m.cfg.CheckHeaderLength(m.contextValue() + text + m.scopeSuffix()); err != nil && m.cfg.EnforceMaxLength {
+			return m, err
+		}
+	case breakingChangeIndex:
+		if text != "" {
+			m.breaking = true
+		}
+	}
+	m.commit[step] = text
+	return m, nil
+}
+
+// the prompt of each step outside the TUI
+var stepPrompts = [nIndices]string{
+	commitTypeIndex:       config.PromptCommitType,
+	scopeIndex:            config.PromptScope,
+	shortDescriptionIndex: config.PromptDescription,
+	breakingChangeIndex:   config.PromptBreakingChangeWhy,
+}
+
+// prompt for each step on its own line, reading answers from `in`. Returns the
+// composed message, or "" if the input ran out or the message is invalid.
+func runPlain(cc *parser.CC, cfg config.Cfg, in io.Reader, out io.Writer) string {
+	m := initialModel(make(chan string, 1), cc, cfg)
+	lines := bufio.NewScanner(in)
+	for _, step := range m.steps {
+		if m.shouldSkip(step) {
+			continue
+		}
+		switch step {
+		case commitTypeIndex:
+			printOptions(out, cfg.CommitTypes)
+		case scopeIndex:
+			printOptions(out, cfg.AllowedScopes(m.commit[commitTypeIndex], cfg.SortedScopes()))
+			fmt.Fprintln(out, "  (leave blank for no scope)")
+		case breakingChangeIndex:
+			fmt.Fprintln(out, "  (leave blank if nothing breaks)")
+		}
+		for {
+			fmt.Fprintf(out, "%s ", strings.TrimSpace(cfg.Prompt(stepPrompts[step])))
+			if !lines.Scan() {
+				fmt.Fprintln(out)
+				return ""
+			}
+			var err error
+			if m, err = m.answer(step, strings.TrimSpace(lines.Text())); err == nil {
+				break
+			}
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+	for _, token := range cfg.MissingFooters(m.commit[commitTypeIndex], m.allFooters()) {
+		fmt.Fprintf(out, "  (%s)\n", config.Tf(config.ErrorFooterRequired, m.commit[commitTypeIndex], token))
+		for {
+			fmt.Fprintf(out, "%s: ", token)
+			if !lines.Scan() {
+				fmt.Fprintln(out)
+				return ""
+			}
+			footer := token + ": " + strings.TrimSpace(lines.Text())
+			err := checkRequiredFooter(token, footer)
+			if err == nil {
+				m.requiredFooters = append(m.requiredFooters, footer)
+				break
+			}
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+	value, warnings, err := m.compose()
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(out, "error: %v\n", err)
+		return ""
+	}
+	if m.breaking && cfg.ConfirmBreaking {
+		fmt.Fprintf(out, "%s ", breakingQuestion(strings.TrimSpace(m.commit[breakingChangeIndex])))
+		if !lines.Scan() || strings.ToLower(strings.TrimSpace(lines.Text())) != "y" {
+			fmt.Fprintln(out, "aborted")
+			return ""
+		}
+	}
+	return value
+}
+
+// the message the answered steps compose and any warnings about it, or an
+// error if it shouldn't be committed.
+func (m model) compose() (string, []string, error) {
+	if err := m.validateType(); err != nil {
+		return "", nil, err
+	}
+	if err := m.cfg.CheckScopeRequired(m.commit[commitTypeIndex], m.commit[scopeIndex]); err != nil {
+		return "", nil, err
+	}
+	if err := m.cfg.CheckScopeAllowed(m.commit[commitTypeIndex], m.commit[scopeIndex]); err != nil {
+		return "", nil, err
+	}
+	if missing := m.cfg.MissingFooters(m.commit[commitTypeIndex], m.allFooters()); len(missing) > 0 {
+		return "", nil, config.MissingFootersError(m.commit[commitTypeIndex], missing)
+	}
+	if m.cfg.NeedsBreakingChangeDescription(m.breaking, m.allFooters()) {
+		return "", nil, config.BreakingChangeDescriptionError()
+	}
+	warnings := []string{}
+	if m.filterErr != nil {
+		warnings = append(warnings, filterWarning(m.filterErr).Error())
+	}
+	if missingRevertRef(m.commit[commitTypeIndex], m.body, m.allFooters()) {
+		warnings = append(warnings, config.T(config.WarningRevertRef))
+	}
+	if placeholder := m.cfg.Placeholder(m.commit[shortDescriptionIndex]); placeholder != "" {
+		warnings = append(warnings, config.Tf(config.WarningPlaceholder, placeholder))
+	}
+	if hint := moodHint(m.commit[shortDescriptionIndex], m.cfg); hint != "" {
+		warnings = append(warnings, hint)
+	}
+	if err := m.cfg.CheckBodyLength(m.body); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	value := m.value()
+	if err := checkRoundTrip(value, m.expected(), m.cfg); err != nil {
+		return "", warnings, err
+	}
+	return value, warnings, nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+func TestPlainPrompts(t *testing.T) {
+	in := strings.NewReader("nope\nfeat\nweb\ncli\nadd a flag\nremoves -x\n")
+	out := &bytes.Buffer{}
+	result := runPlain(&parser.CC{}, testCfg, in, out)
+	expected := "feat(cli)!: add a flag\n\nBREAKING CHANGE: removes -x\n"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+	for _, problem := range []string{"unknown type 'nope'", "unknown scope 'web'"} {
+		if !strings.Contains(out.String(), problem) {
+			t.Errorf("expected the output to mention %s, got %q", problem, out.String())
+		}
+	}
+}
+
+func TestPlainPromptsSkipValidSteps(t *testing.T) {
+	in := strings.NewReader("\nadd a flag\n\n")
+	result := runPlain(&parser.CC{Type: "feat"}, testCfg, in, &bytes.Buffer{})
+	if result != "feat: add a flag\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestPlainPromptsRunningOutOfInput(t *testing.T) {
+	if result := runPlain(&parser.CC{}, testCfg, strings.NewReader("feat\n"), &bytes.Buffer{}); result != "" {
+		t.Errorf("expected no message without a description, got %q", result)
+	}
+}
+
+func TestPlainPromptsCheckTheTypeCase(t *testing.T) {
+	cfg := testCfg
+	cfg.TypeCase = config.CaseLower
+	out := &bytes.Buffer{}
+	result := runPlain(&parser.CC{}, cfg, strings.NewReader("Feat\nfeat\n\nx\n\n"), out)
+	if result != "feat: x\n" || !strings.Contains(out.String(), "should be lower-case") {
+		t.Errorf("expected 'Feat' to be rejected, got %q and %q", result, out.String())
+	}
+	cfg.NormalizeTypeCase = true
+	result = runPlain(&parser.CC{}, cfg, strings.NewReader("Feat\n\nx\n\n"), &bytes.Buffer{})
+	if result != "feat: x\n" {
+		t.Errorf("expected 'Feat' to be lower-cased, got %q", result)
+	}
+}
+
+func TestPlainPromptsRequireScopesByType(t *testing.T) {
+	cfg := testCfg
+	cfg.RequireScopeFor = []string{"feat"}
+	out := &bytes.Buffer{}
+	result := runPlain(&parser.CC{}, cfg, strings.NewReader("feat\n\ncli\nx\n\n"), out)
+	if result != "feat(cli): x\n" || !strings.Contains(out.String(), "'feat' commits need a scope") {
+		t.Errorf("expected an empty scope to be rejected, got %q and %q", result, out.String())
+	}
+}
+
+func TestPlainPromptsConfirmBreakingChanges(t *testing.T) {
+	cfg := testCfg
+	cfg.ConfirmBreaking = true
+	for answer, expected := range map[string]string{
+		"y\n": "feat!: x\n\nBREAKING CHANGE: drops y\n",
+		"n\n": "",
+		"":    "",
+	} {
+		out := &bytes.Buffer{}
+		result := runPlain(&parser.CC{Type: "feat"}, cfg, strings.NewReader("\nx\ndrops y\n"+answer), out)
+		if result != expected || !strings.Contains(out.String(), "marked BREAKING: drops y. Continue?") {
+			t.Errorf("answering %q: expected %q, got %q and %q", answer, expected, result, out.String())
+		}
+	}
+}
+
+func TestPlainPromptsAskForRequiredFooters(t *testing.T) {
+	cfg := testCfg
+	cfg.RequiredFooters = map[string][]string{"feat": {"Refs"}}
+	out := &bytes.Buffer{}
+	result := runPlain(&parser.CC{Type: "feat"}, cfg, strings.NewReader("\nx\n\n\n#1\n"), out)
+	if result != "feat: x\n\nRefs: #1\n" || !strings.Contains(out.String(), "'feat' commits need a Refs footer") {
+		t.Errorf("expected to be asked for the footer, got %q and %q", result, out.String())
+	}
+	if !strings.Contains(out.String(), "error: "+config.T(config.ErrorRequired)) {
+		t.Errorf("expected an empty footer to be refused, got %q", out.String())
+	}
+}
+
+func TestPlainPromptsWarnAboutLongBodies(t *testing.T) {
+	cfg := testCfg
+	cfg.BodyMaxLength = 5
+	out := &bytes.Buffer{}
+	cc := &parser.CC{Type: "feat", Body: "so it works"}
+	result := runPlain(cc, cfg, strings.NewReader("\nx\n\n"), out)
+	if result != "feat: x\n\nso it works\n" || !strings.Contains(out.String(), "warning: the body is 11 characters long") {
+		t.Errorf("expected a warning about the body, got %q and %q", result, out.String())
+	}
+}
+
+func TestPlainPromptsWarnAboutFailingFilters(t *testing.T) {
+	cfg := testCfg
+	cfg.DescriptionFilter = "exit 3"
+	out := &bytes.Buffer{}
+	result := runPlain(&parser.CC{Type: "feat"}, cfg, strings.NewReader("\nx\n\n"), out)
+	if result != "feat: x\n" || !strings.Contains(out.String(), "warning: description_filter failed") {
+		t.Errorf("expected a warning about the filter, got %q and %q", result, out.String())
+	}
+}
+
+func TestPlainPromptsNarrowScopesByType(t *testing.T) {
+	cfg := testCfg
+	cfg.Scopes = []map[string]string{{"api": "the api"}, {"cli": "the cli"}}
+	cfg.ScopesByType = map[string][]string{"feat": {"cli"}}
+	out := &bytes.Buffer{}
+	result := runPlain(&parser.CC{}, cfg, strings.NewReader("feat\napi\ncli\nx\n\n"), out)
+	if result != "feat(cli): x\n" || !strings.Contains(out.String(), "'feat' commits can't have the scope 'api'") {
+		t.Errorf("expected the api scope to be rejected, got %q and %q", result, out.String())
+	}
+	if strings.Contains(out.String(), "the api") {
+		t.Errorf("expected only feat's scopes to be listed, got %q", out.String())
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// the kinds of protocolMessage
+const (
+	protocolStep    = "step"    // asks for the answer to a step
+	protocolError   = "error"   // rejects an answer or the composed message
+	protocolWarning = "warning" // flags a problem that doesn't block the commit
+	protocolDone    = "message" // the composed message, which git-cc then commits
+)
+
+// an option of the commit_type or scope steps
+type protocolOption struct {
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+// a line of JSON that --protocol mode writes for an editor plugin to render
+type protocolMessage struct {
+	Kind string `json:"kind"`
+	// the name of the step from the steps config, e.g. "scope"
+	Step    string           `json:"step,omitempty"`
+	Prompt  string           `json:"prompt,omitempty"`
+	Options []protocolOption `json:"options,omitempty"`
+	// the step's current value, or the composed message
+	Value string `json:"value"`
+	// the error or warning
+	Message string `json:"message,omitempty"`
+}
+
+// a line of JSON answering the last step. The step is optional, but if given
+// it must be the step that was asked.
+type protocolAnswer struct {
+	Step  string `json:"step"`
+	Value string `json:"value"`
+}
+
+func toProtocolOptions(options []map[string]string) []protocolOption {
+	result := []protocolOption{}
+	for _, option := range options {
+		names := make([]string, 0, len(option))
+		for name := range option {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			result = append(result, protocolOption{name, option[name]})
+		}
+	}
+	return result
+}
+
+// drive the steps with JSON lines: for each step, write a "step" message to
+// `out` and read an answer from `in`, writing an "error" message and asking
+// again if the answer is invalid. Returns the composed message, which is also
+// written as a "message" message, or "" if the input ran out or the message is
+// invalid.
+func runProtocol(cc *parser.CC, cfg config.Cfg, in io.Reader, out io.Writer) string {
+	m := initialModel(make(chan string, 1), cc, cfg)
+	encoder := json.NewEncoder(out)
+	emit := func(message protocolMessage) {
+		if err := encoder.Encode(message); err != nil {
+			log.Fatal(err)
+		}
+	}
+	decoder := json.NewDecoder(in)
+	for _, step := range m.steps {
+		if m.shouldSkip(step) {
+			continue
+		}
+		request := protocolMessage{
+			Kind:   protocolStep,
+			Step:   stepNames[step],
+			Prompt: strings.TrimSpace(cfg.Prompt(stepPrompts[step])),
+			Value:  m.commit[step],
+		}
+		switch step {
+		case commitTypeIndex:
+			request.Options = toProtocolOptions(cfg.CommitTypes)
+		case scopeIndex:
+			request.Options = toProtocolOptions(cfg.SortedScopes())
+		}
+		for {
+			emit(request)
+			var answer protocolAnswer
+			if err := decoder.Decode(&answer); err == io.EOF {
+				return ""
+			} else if err != nil {
+				emit(protocolMessage{Kind: protocolError, Step: request.Step, Message: fmt.Sprintf("invalid answer: %v", err)})
+				return ""
+			}
+			if answer.Step != "" && answer.Step != request.Step {
+				emit(protocolMessage{
+					Kind: protocolError, Step: request.Step,
+					Message: fmt.Sprintf("expected an answer to %s, got %s", request.Step, answer.Step),
+				})
+				continue
+			}
+			var err error
+			if m, err = m.answer(step, strings.TrimSpace(answer.Value)); err == nil {
+				break
+			}
+			emit(protocolMessage{Kind: protocolError, Step: request.Step, Message: err.Error()})
+		}
+	}
+	value, warnings, err := m.compose()
+	for _, warning := range warnings {
+		emit(protocolMessage{Kind: protocolWarning, Message: warning})
+	}
+	if err != nil {
+		emit(protocolMessage{Kind: protocolError, Message: err.Error()})
+		return ""
+	}
+	emit(protocolMessage{Kind: protocolDone, Value: value})
+	return value
+}
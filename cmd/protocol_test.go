@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// decode each line of `out`
+func protocolMessages(t *testing.T, out *bytes.Buffer) []protocolMessage {
+	messages := []protocolMessage{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var message protocolMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			t.Fatalf("expected JSON lines, got %q", line)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func TestDrivingTheStepsWithJSON(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		`{"step":"commit_type","value":"nope"}`,
+		`{"value":"feat"}`,
+		`{"step":"description","value":"skips ahead"}`,
+		`{"step":"scope","value":"cli"}`,
+		`{"step":"description","value":"add a flag"}`,
+		`{"step":"breaking_change","value":"removes -x"}`,
+	}, "\n"))
+	out := &bytes.Buffer{}
+	result := runProtocol(&parser.CC{}, testCfg, in, out)
+	expected := "feat(cli)!: add a flag\n\nBREAKING CHANGE: removes -x\n"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+	messages := protocolMessages(t, out)
+	kinds := []string{}
+	for _, message := range messages {
+		kinds = append(kinds, message.Kind+":"+message.Step)
+	}
+	expectedKinds := strings.Join([]string{
+		"step:commit_type", "error:commit_type", "step:commit_type",
+		"step:scope", "error:scope", "step:scope",
+		"step:description", "step:breaking_change", "message:",
+	}, " ")
+	if actual := strings.Join(kinds, " "); actual != expectedKinds {
+		t.Errorf("expected %s, got %s", expectedKinds, actual)
+	}
+	if options := messages[0].Options; len(options) != 2 || options[0].Value != "feat" || options[0].Description != "adds a feature" {
+		t.Errorf("expected the commit types as options, got %v", options)
+	}
+	if messages[1].Message != "unknown type 'nope'" {
+		t.Errorf("unexpected error %q", messages[1].Message)
+	}
+	if last := messages[len(messages)-1]; last.Value != expected {
+		t.Errorf("expected the composed message last, got %q", last.Value)
+	}
+}
+
+func TestSkippingAnsweredStepsInTheProtocol(t *testing.T) {
+	out := &bytes.Buffer{}
+	result := runProtocol(&parser.CC{Type: "feat", Scope: "cli"}, testCfg, strings.NewReader(
+		`{"value":"add a flag"}`+"\n"+`{"value":""}`,
+	), out)
+	if result != "feat(cli): add a flag\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+	if messages := protocolMessages(t, out); messages[0].Step != "description" {
+		t.Errorf("expected to start at the description, got %v", messages[0])
+	}
+	out.Reset()
+	if result := runProtocol(&parser.CC{}, testCfg, strings.NewReader(`{"value":"feat"}`), out); result != "" {
+		t.Errorf("expected no message once the input runs out, got %q", result)
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/helpbar"
+	"github.com/skalt/git-cc/pkg/parser"
+	"github.com/skalt/git-cc/pkg/single_select"
+)
+
+// the conventional headers among `git log --format=%h%x09%s` lines, as
+// {header: short hash} options. Unparseable and repeated headers are skipped.
+func recentHeaders(gitLog string, opts ...parser.Option) []map[string]string {
+	options := []map[string]string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(gitLog, "\n") {
+		hash, header, found := strings.Cut(line, "\t")
+		if !found || seen[header] {
+			continue
+		}
+		if _, err := parser.ParseHeader(header, opts...); err != nil {
+			continue
+		}
+		seen[header] = true
+		options = append(options, map[string]string{header: hash})
+	}
+	return options
+}
+
+// a draft cloning the type and scope of `header`, keeping the description if
+// `keepDescription`.
+func draftFrom(header string, keepDescription bool, opts ...parser.Option) *parser.CC {
+	cc, _ := parser.ParseHeader(header, opts...)
+	if !keepDescription {
+		cc.Description = ""
+	}
+	cc.Bang, cc.BreakingChange = false, false
+	return cc
+}
+
+// whether `query` appears anywhere in `option`, ignoring case
+func matchAnywhere(m *single_select.Model, query string, option string) bool {
+	return strings.Contains(strings.ToLower(option), strings.ToLower(query))
+}
+
+// picks one of the recent headers, sending it to `choice` ("" if cancelled).
+type recentModel struct {
+	input   single_select.Model
+	helpBar helpbar.Model
+	choice  chan string
+}
+
+func newRecentModel(choice chan string, options []map[string]string, cfg config.Cfg) recentModel {
+	return recentModel{
+		input: single_select.NewModel(
+			config.Faint(cfg.Prompt(config.PromptRecentCommit)), "", options, matchAnywhere,
+		),
+		helpBar: helpbar.NewModel(config.HelpSubmit, config.HelpSelect, config.HelpCancel),
+		choice:  choice,
+	}
+}
+
+func (m recentModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m recentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			m.choice <- ""
+			return m, tea.Quit
+		case tea.KeyEnter, tea.KeyTab:
+			if value := m.input.Value(); value != "" {
+				m.choice <- value
+				return m, tea.Quit
+			}
+			return m, cmd
+		}
+	case tea.WindowSizeMsg:
+		m.helpBar, _ = m.helpBar.Update(msg)
+	}
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m recentModel) View() string {
+	return m.input.View() + "\n" + m.helpBar.View() + "\n"
+}
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "start a commit from the type and scope of a recent commit",
+	Long: `pick one of the most recent conventional commits and start a new commit
+with the same type and scope, e.g. for a series of related commits.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, _ := cmd.Flags().GetInt("count")
+		keep, _ := cmd.Flags().GetBool("keep-description")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		cfg := loadConfig(cmd)
+		gitLog, err := config.Runner.Output("log", "--format=%h%x09%s", "-n", strconv.Itoa(count))
+		if err != nil {
+			log.Fatal(err)
+		}
+		options := recentHeaders(gitLog, cfg.ParserOption())
+		if len(options) == 0 {
+			log.Fatalf("none of the last %d commits are conventional commits", count)
+		}
+		choice := make(chan string, 1)
+		if err := tea.NewProgram(newRecentModel(choice, options, cfg)).Start(); err != nil {
+			log.Fatal(err)
+		}
+		header := <-choice
+		if header == "" {
+			os.Exit(1)
+		}
+		result := runTUI(draftFrom(header, keep, cfg.ParserOption()), cfg)
+		if result == "" {
+			os.Exit(1)
+		}
+		commitWithRetries(result, cfg, dryRun, getGitCommitCmd(cmd), nil)
+	},
+}
+
+func init() {
+	recentCmd.Flags().Int("count", 10, "how many of the most recent commits to choose from")
+	recentCmd.Flags().Bool("keep-description", false, "start with the chosen commit's description rather than an empty one")
+	recentCmd.Flags().Bool("dry-run", false, "Only print the resulting conventional commit message; don't commit.")
+	recentCmd.Flags().Bool("no-edit", false, "Use the selected commit message without launching an editor.")
+	Cmd.AddCommand(recentCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+func TestRecentHeaders(t *testing.T) {
+	gitLog := "abc1234\tfeat(cli): add x\n" +
+		"bcd2345\tMerge branch 'topic'\n" +
+		"cde3456\tfix: y\n" +
+		"def4567\tfeat(cli): add x\n"
+	expected := []map[string]string{{"feat(cli): add x": "abc1234"}, {"fix: y": "cde3456"}}
+	if actual := recentHeaders(gitLog); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestDraftingFromARecentCommit(t *testing.T) {
+	cc := draftFrom("feat(cli)!: add x", false)
+	if cc.Type != "feat" || cc.Scope != "cli" || cc.Description != "" || cc.Bang {
+		t.Errorf("expected a non-breaking feat(cli) draft without a description, got %+v", cc)
+	}
+	if cc = draftFrom("feat(cli): add x", true); cc.Description != "add x" {
+		t.Errorf("expected the description to be kept, got %q", cc.Description)
+	}
+}
+
+func TestPickingARecentCommit(t *testing.T) {
+	choice := make(chan string, 1)
+	cfg := testCfg
+	cfg.Prompts = map[string]string{config.PromptRecentCommit: "start from:"}
+	var m tea.Model = newRecentModel(choice, []map[string]string{{"feat(cli): add x": "abc1234"}, {"fix: y": "cde3456"}}, cfg)
+	if view := m.View(); !strings.Contains(view, "start from:") {
+		t.Errorf("expected the configured prompt, got %q", view)
+	}
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("fix")},
+		{Type: tea.KeyEnter},
+	} {
+		m, _ = m.Update(key)
+	}
+	if result := <-choice; result != "fix: y" {
+		t.Errorf("expected %q, got %q", "fix: y", result)
+	}
+}
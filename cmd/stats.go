@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// how often each type and scope appears in a commit history
+type commitStats struct {
+	Total           int            `json:"total"`
+	NonConventional int            `json:"non_conventional"`
+	Types           map[string]int `json:"types"`
+	Scopes          map[string]int `json:"scopes"`
+	Unscoped        int            `json:"unscoped"`
+}
+
+// count the header on each line of `headers` without reading them all first,
+// since histories can be long.
+func tallyHeaders(headers io.Reader, cfg config.Cfg) (commitStats, error) {
+	stats := commitStats{Types: map[string]int{}, Scopes: map[string]int{}}
+	scanner := bufio.NewScanner(headers)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // allow long subjects
+	for scanner.Scan() {
+		stats.Total++
+		cc, err := parser.ParseHeader(scanner.Text(), cfg.ParserOption())
+		if err != nil || !parser.IsSingleToken(cc.Type) {
+			stats.NonConventional++
+			continue
+		}
+		readScopeSuffix(cc, cfg)
+		stats.Types[cc.Type]++
+		if cc.Scope == "" {
+			stats.Unscoped++
+		} else {
+			stats.Scopes[cc.Scope]++
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// `part` as a percentage of `total`
+func percent(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
+
+// print `counts` from most to least common as a table of names, counts, and
+// their percentage of `total`
+func printCounts(out io.Writer, counts map[string]int, total int) {
+	names := make([]string, 0, len(counts))
+	width := 0
+	for name := range counts {
+		names = append(names, name)
+		if config.Width(name) > width {
+			width = config.Width(name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		padding := width - config.Width(name)
+		fmt.Fprintf(out, "  %s%*s %5d %5.1f%%\n", name, padding, "", counts[name], percent(counts[name], total))
+	}
+}
+
+func (stats commitStats) print(out io.Writer) {
+	fmt.Fprintf(
+		out, "%d commits, %d (%.1f%%) not conventional\n",
+		stats.Total, stats.NonConventional, percent(stats.NonConventional, stats.Total),
+	)
+	conventional := stats.Total - stats.NonConventional
+	if conventional == 0 {
+		return
+	}
+	fmt.Fprintln(out, "\ntypes:")
+	printCounts(out, stats.Types, conventional)
+	fmt.Fprintln(out, "\nscopes:")
+	scopes := map[string]int{"(none)": stats.Unscoped}
+	for scope, count := range stats.Scopes {
+		scopes[scope] = count
+	}
+	printCounts(out, scopes, conventional)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [range]",
+	Short: "count the commit types and scopes in the history, e.g. to tune the config",
+	Long: `count how often each commit type and scope appears in the headers of
+the commits in [range], which defaults to HEAD, along with the share of
+commits that aren't conventional commits.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+		logArgs := []string{"log", "--format=%s"}
+		if noMerges, _ := cmd.Flags().GetBool("no-merges"); noMerges {
+			logArgs = append(logArgs, "--no-merges")
+		}
+		logArgs = append(logArgs, args...)
+		// stream the log rather than holding the whole history in memory
+		headers, gitLog := io.Pipe()
+		done := make(chan error, 1)
+		go func() {
+			err := config.Runner.Run(nil, gitLog, os.Stderr, logArgs...)
+			gitLog.CloseWithError(err)
+			done <- err
+		}()
+		stats, err := tallyHeaders(headers, cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = <-done; err != nil {
+			log.Fatal(err)
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(stats); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			stats.print(os.Stdout)
+		}
+	},
+}
+
+func init() {
+	statsCmd.Flags().Bool("json", false, "print the counts as JSON")
+	statsCmd.Flags().Bool("no-merges", false, "skip merge commits")
+	Cmd.AddCommand(statsCmd)
+}
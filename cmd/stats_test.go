@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTallyingHeaders(t *testing.T) {
+	history := strings.Join([]string{
+		"feat(cli): add a flag",
+		"fix(cli)!: stop crashing",
+		"feat: support x",
+		"Merge branch 'main'",
+		"docs(readme): explain stats",
+		"wip",
+	}, "\n") + "\n"
+	stats, err := tallyHeaders(strings.NewReader(history), testCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total != 6 || stats.NonConventional != 2 || stats.Unscoped != 1 {
+		t.Errorf("unexpected totals %+v", stats)
+	}
+	if fmt.Sprint(stats.Types) != "map[docs:1 feat:2 fix:1]" {
+		t.Errorf("unexpected types %v", stats.Types)
+	}
+	if fmt.Sprint(stats.Scopes) != "map[cli:2 readme:1]" {
+		t.Errorf("unexpected scopes %v", stats.Scopes)
+	}
+	out := &strings.Builder{}
+	stats.print(out)
+	for _, line := range []string{
+		"6 commits, 2 (33.3%) not conventional",
+		"  feat     2  50.0%",
+		"  cli        2  50.0%",
+		"  (none)     1  25.0%",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected %q in:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestTallyingAnEmptyHistory(t *testing.T) {
+	stats, err := tallyHeaders(strings.NewReader(""), testCfg)
+	if err != nil || stats.Total != 0 {
+		t.Fatalf("unexpected stats %+v (%v)", stats, err)
+	}
+	out := &strings.Builder{}
+	stats.print(out)
+	if out.String() != "0 commits, 0 (0.0%) not conventional\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+// the default name of the generated commit template
+const gitMessageFile = ".gitmessage"
+
+// a commit.template for plain `git commit` listing the configured types and
+// scopes as comments, which git strips from the message.
+func gitMessageTemplate(cfg config.Cfg) string {
+	s := strings.Builder{}
+	s.WriteString("\n")
+	s.WriteString("# <type>(<scope>): <description>\n")
+	s.WriteString("#\n")
+	s.WriteString("# [optional body]\n")
+	s.WriteString("#\n")
+	s.WriteString("# [optional footers, e.g. BREAKING CHANGE: <explanation>]\n")
+	sections := []struct {
+		title   string
+		options []map[string]string
+	}{
+		{"types", cfg.CommitTypes},
+		{"scopes", cfg.Scopes},
+	}
+	for _, section := range sections {
+		if len(section.options) == 0 {
+			continue
+		}
+		fmt.Fprintf(&s, "#\n# %s:\n", section.title)
+		for _, opt := range sortedOptions(section.options) {
+			fmt.Fprintf(&s, "#   %s: %s\n", opt[0], opt[1])
+		}
+	}
+	return s.String()
+}
+
+// write `contents` to `path` unless it already holds different contents and
+// `force` is false. Returns whether the file changed.
+func writeTemplate(path string, contents string, force bool) (bool, error) {
+	if existing, err := os.ReadFile(path); err == nil {
+		if string(existing) == contents {
+			return false, nil
+		}
+		if !force {
+			return false, fmt.Errorf("%s already exists; pass --force to overwrite it", path)
+		}
+	}
+	return true, config.WriteFileAtomic(path, []byte(contents), 0644)
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "write a .gitmessage listing the configured types and scopes and set it as git's commit.template",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+		global, _ := cmd.Flags().GetBool("global")
+		force, _ := cmd.Flags().GetBool("force")
+		var path string
+		gitArgs := []string{"config"}
+		if global {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				log.Fatal(err)
+			}
+			path = filepath.Join(home, gitMessageFile)
+			gitArgs = append(gitArgs, "--global", "commit.template", path)
+		} else {
+			root, err := config.Runner.Output("rev-parse", "--show-toplevel")
+			if err != nil {
+				log.Fatal(err)
+			}
+			path = filepath.Join(strings.TrimSpace(root), gitMessageFile)
+			// relative to the top level, where git resolves it
+			gitArgs = append(gitArgs, "commit.template", gitMessageFile)
+		}
+		changed, err := writeTemplate(path, gitMessageTemplate(cfg), force)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if changed {
+			fmt.Fprintf(os.Stderr, "wrote %s\n", path)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s is up to date\n", path)
+		}
+		if _, err := config.Runner.Output(gitArgs...); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "set commit.template to %s\n", gitArgs[len(gitArgs)-1])
+	},
+}
+
+func init() {
+	templateCmd.Flags().Bool("global", false, "write ~/.gitmessage and set commit.template in your global git config")
+	templateCmd.Flags().Bool("force", false, "overwrite an existing template with different contents")
+	configCmd.AddCommand(templateCmd)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWritingAGitMessageTemplate(t *testing.T) {
+	template := gitMessageTemplate(testCfg)
+	for _, expected := range []string{"#   feat: ", "#   cli: "} {
+		if !strings.Contains(template, expected) {
+			t.Errorf("expected %q in the template, got %q", expected, template)
+		}
+	}
+	path := filepath.Join(t.TempDir(), gitMessageFile)
+	if changed, err := writeTemplate(path, template, false); err != nil || !changed {
+		t.Fatalf("expected a new template, got changed=%v, %v", changed, err)
+	}
+	if changed, err := writeTemplate(path, template, false); err != nil || changed {
+		t.Errorf("expected rewriting the same template to be a no-op, got changed=%v, %v", changed, err)
+	}
+	if _, err := writeTemplate(path, "# other\n", false); err == nil {
+		t.Error("expected an error overwriting a different template")
+	}
+	if changed, err := writeTemplate(path, "# other\n", true); err != nil || !changed {
+		t.Errorf("expected --force to overwrite, got changed=%v, %v", changed, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# other\n" {
+		t.Errorf("unexpected template %q", data)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/skalt/git-cc/pkg/breaking_change_input"
 	"github.com/skalt/git-cc/pkg/config"
 	"github.com/skalt/git-cc/pkg/description_editor"
+	"github.com/skalt/git-cc/pkg/footer_input"
+	"github.com/skalt/git-cc/pkg/footer_review"
 	"github.com/skalt/git-cc/pkg/parser"
 	"github.com/skalt/git-cc/pkg/scope_selector"
 	"github.com/skalt/git-cc/pkg/type_selector"
@@ -27,12 +30,18 @@ const ( // the order of the components
 var (
 	boolFlags = [...]string{
 		"all",
+		"allow-empty",
 		"signoff",
 		"no-signoff",
 		"no-post-rewrite",
 		"no-gpg-sign",
 		"no-verify", // https://git-scm.com/docs/git-commit#Documentation/git-commit.txt---no-verify
 	}
+	// flags passed to git commit with their values, which git validates
+	stringFlags = [...]string{
+		"author",
+		"date",
+	}
 )
 
 type InputComponent interface {
@@ -43,6 +52,19 @@ type InputComponent interface {
 type model struct {
 	commit  [nIndices]string
 	viewing componentIndex
+	// the steps to show, in order
+	steps []componentIndex
+	// the body and any footers other than breaking changes carried over from
+	// the initial commit
+	body    string
+	footers []string
+	// text after the footers that isn't a footer, e.g. a signature block
+	trailing string
+	// whether the initial commit's header had a `!`
+	bang bool
+	// whether the commit is marked as a breaking change
+	breaking bool
+	pasting  pasteBuffer
 
 	typeInput           type_selector.Model
 	scopeInput          scope_selector.Model
@@ -51,6 +73,36 @@ type model struct {
 	// the width of the terminal; needed for instantiating components
 	// width  int
 	choice chan string
+	cfg    config.Cfg
+	// why the commit was aborted, if it was
+	err error
+	// whether the user was warned about a revert without a reference, so
+	// submitting again commits anyway
+	warnedRevert bool
+	// whether the user was warned about a placeholder description
+	warnedPlaceholder bool
+	// whether the user was warned about a body over body_max_length
+	warnedBodyLength bool
+	// whether the description_filter is running, and the description it last
+	// returned, which submitting again doesn't filter again
+	filtering           bool
+	filteredDescription string
+	// why the description_filter last failed, if it did
+	filterErr error
+	// whether submitting waits on confirming the breaking change, and whether
+	// it's been confirmed; see confirm_breaking
+	confirmingBreaking bool
+	confirmedBreaking  bool
+	// the footers as reviewed before submitting, once they've been reviewed;
+	// see review_footers
+	footerReview     footer_review.Model
+	reviewingFooters bool
+	reviewedFooters  []string
+	// asks for a footer that required_footers needs before submitting, and
+	// the footers it's been given
+	footerInput     footer_input.Model
+	askingFooter    bool
+	requiredFooters []string
 }
 
 // returns whether the minimum requirements for a conventional commit are met.
@@ -63,31 +115,85 @@ func (m model) contextValue() string {
 	result := strings.Builder{}
 	result.WriteString(m.commit[commitTypeIndex])
 	scope := m.commit[scopeIndex]
-	breakingChange := m.commit[breakingChangeIndex]
-	if scope != "" {
+	explained := strings.TrimSpace(m.commit[breakingChangeIndex]) != ""
+	if scope != "" && m.cfg.ScopePosition != config.ScopeSuffix {
 		result.WriteString(fmt.Sprintf("(%s)", scope))
 	}
-	if breakingChange != "" {
+	// unexplained breaking changes need a `!` to mark them at all
+	if m.breaking && (m.bang || !explained || m.cfg.BreakingChangeBang) {
 		result.WriteRune('!')
 	}
-	result.WriteString(": ")
+	result.WriteString(m.cfg.Separator())
 	return result.String()
 }
 
+// returns the ` (scope)` following the description in the scope-last layout
+func (m model) scopeSuffix() string {
+	if m.cfg.ScopePosition != config.ScopeSuffix {
+		return ""
+	}
+	return parser.JoinScopeSuffix("", m.commit[scopeIndex])
+}
+
+// the footers to emit: breaking changes, any carried-over footers, and the
+// configured default footers that aren't already present, in footer_order.
+func (m model) allFooters() []string {
+	if m.cfg.Minimal {
+		return m.requiredFooters
+	}
+	if m.reviewingFooters {
+		return m.footerReview.Footers()
+	} else if m.reviewedFooters != nil {
+		return m.reviewedFooters
+	}
+	footers := []string{}
+	breakingChange := strings.TrimSpace(m.commit[breakingChangeIndex])
+	if m.breaking && breakingChange != "" {
+		footers = append(footers, "BREAKING CHANGE: "+breakingChange)
+	}
+	footers = append(footers, m.footers...)
+	footers = append(footers, m.requiredFooters...)
+	return m.cfg.OrderFooters(m.cfg.NormalizeFooters(
+		parser.MergeFooters(footers, m.cfg.FootersFor(m.commit[commitTypeIndex])),
+	))
+}
+
 // Returns a pretty-printed CC string. The model should be `.ready()` before you call `.value()`.
 func (m model) value() string {
 	result := strings.Builder{}
 	result.WriteString(m.contextValue())
 	result.WriteString(m.commit[shortDescriptionIndex])
+	result.WriteString(m.scopeSuffix())
 	result.WriteString("\n")
-	breakingChange := m.commit[breakingChangeIndex]
-	if breakingChange != "" {
-		result.WriteString(fmt.Sprintf("\n\nBREAKING CHANGE: %s\n", breakingChange))
-		// TODO: handle multiple breaking change footers(?)
+	if m.body != "" {
+		result.WriteString("\n" + m.body + "\n")
+	}
+	if footers := m.allFooters(); len(footers) > 0 {
+		result.WriteString("\n")
+		for _, footer := range footers {
+			result.WriteString(footer + "\n")
+		}
+	}
+	if m.trailing != "" {
+		result.WriteString("\n" + m.trailing + "\n")
 	}
 	return result.String()
 }
 
+// returns an error unless the composed header parses back to exactly the one
+// selected commit type.
+func (m model) validateType() error {
+	selected := m.commit[commitTypeIndex]
+	cc, err := parser.ParseHeader(m.contextValue(), m.cfg.ParserOption())
+	if err != nil {
+		return config.Error(config.ErrorInvalidType, selected, err)
+	}
+	if cc.Type != selected || !parser.IsSingleToken(cc.Type) {
+		return config.Error(config.ErrorTypeNotOneWord, selected)
+	}
+	return nil
+}
+
 func (m model) Init() tea.Cmd {
 	return nil
 }
@@ -107,37 +213,58 @@ func (m model) currentComponent() InputComponent {
 func initialModel(choice chan string, cc *parser.CC, cfg config.Cfg) model {
 	typeModel := type_selector.NewModel(cc, cfg)
 	scopeModel := scope_selector.NewModel(cc, cfg)
+	if scope, ok := cfg.DefaultScopeByType[cc.Type]; ok && cc.Type != "" && cc.Scope == "" {
+		scopeModel = scopeModel.Highlight(scope)
+	}
 	descModel := description_editor.NewModel(
 		cfg.HeaderMaxLength, cc.Description, cfg.EnforceMaxLength,
-	)
-	bcModel := breaking_change_input.NewModel()
-	breakingChanges := ""
-	if cc.BreakingChange {
-		for _, footer := range cc.Footers {
-			result, err := parser.BreakingChange([]rune(footer))
-			if err == nil {
-				breakingChanges += string(result.Remaining) + "\n"
-			}
+	).SetPrompt(cfg.Prompt(config.PromptDescription)).SetRuler(cfg.LengthRuler)
+	if cfg.SubjectMood == config.MoodImperative {
+		descModel = descModel.SetHint(func(description string) string {
+			return moodHint(description, cfg)
+		})
+	}
+	breakingChanges := []string{}
+	footers := []string{}
+	for _, footer := range cc.Footers {
+		result, err := parser.Sequence(parser.BreakingChange, parser.ColonSep)([]rune(footer))
+		if err == nil {
+			breakingChanges = append(breakingChanges, strings.TrimSpace(string(result.Remaining)))
+		} else {
+			footers = append(footers, footer)
 		}
 	}
+	// TODO: handle multiple breaking change footers(?)
+	explanation := strings.Join(breakingChanges, " ")
+	breaking := (cc.BreakingChange || cc.Bang || explanation != "") && !cfg.Minimal
+	bcModel := breaking_change_input.NewModel(cfg, breaking, explanation)
 	commit := [nIndices]string{
 		cc.Type,
 		cc.Scope,
 		cc.Description,
-		breakingChanges,
+		explanation,
+	}
+	if cfg.Minimal {
+		commit[scopeIndex], commit[breakingChangeIndex] = "", ""
 	}
 	m := model{
 		choice:              choice,
+		cfg:                 cfg,
 		commit:              commit,
+		body:                cc.Body,
+		footers:             footers,
+		trailing:            cc.Trailing,
+		bang:                cc.Bang,
+		breaking:            breaking,
 		typeInput:           typeModel,
 		scopeInput:          scopeModel,
 		descriptionInput:    descModel,
 		breakingChangeInput: bcModel,
-		viewing:             commitTypeIndex,
+		steps:               flow(cfg),
 	}
+	m.viewing = m.steps[0]
 	if m.shouldSkip(m.viewing) {
 		m = m.submit().advance()
-		m.descriptionInput = m.descriptionInput.SetPrefix(m.contextValue())
 	}
 	return m
 }
@@ -158,7 +285,61 @@ func (m model) updateCurrentInput(msg tea.Msg) (model, tea.Cmd) {
 	return m, cmd
 }
 
+// the name of each step in the steps config
+var stepNames = [nIndices]string{
+	commitTypeIndex:       config.StepCommitType,
+	scopeIndex:            config.StepScope,
+	shortDescriptionIndex: config.StepDescription,
+	breakingChangeIndex:   config.StepBreakingChange,
+}
+
+// the configured steps, in order. Minimal mode leaves out the scope and
+// breaking-change steps.
+func flow(cfg config.Cfg) []componentIndex {
+	names := cfg.Steps
+	if len(names) == 0 {
+		names = config.DefaultSteps
+	}
+	indices := map[string]componentIndex{}
+	for step, name := range stepNames {
+		indices[name] = componentIndex(step)
+	}
+	steps := []componentIndex{}
+	for _, name := range names {
+		step := indices[name]
+		if cfg.Minimal && (step == scopeIndex || step == breakingChangeIndex) {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// the position of the current step in the flow, or -1 if it's not a step
+func (m model) position() int {
+	for i, step := range m.steps {
+		if step == m.viewing {
+			return i
+		}
+	}
+	return -1
+}
+
+// whether a step is left out of the flow entirely, as opposed to being skipped
+// because it's already filled in.
+func (m model) hidden(component componentIndex) bool {
+	for _, step := range m.steps {
+		if step == component {
+			return false
+		}
+	}
+	return true
+}
+
 func (m model) shouldSkip(component componentIndex) bool {
+	if m.hidden(component) {
+		return true
+	}
 	switch component {
 	case commitTypeIndex:
 		return m.typeInput.ShouldSkip(m.commit[commitTypeIndex])
@@ -169,41 +350,363 @@ func (m model) shouldSkip(component componentIndex) bool {
 	}
 }
 
+// move to the next step that isn't skipped, or to nIndices after the last.
+// A hidden step that finish() showed goes straight to nIndices.
 func (m model) advance() model { // TODO: consider submitting w/in this fn
-	for {
-		m.viewing++
-		if !m.shouldSkip(m.viewing) {
-			break
+	if m.position() < 0 {
+		m.viewing = nIndices
+		return m
+	}
+	for i := m.position() + 1; i < len(m.steps); i++ {
+		if !m.shouldSkip(m.steps[i]) {
+			m.viewing = m.steps[i]
+			return m
 		}
 	}
+	m.viewing = nIndices
+	return m
+}
+
+// go back to the previous step in the flow, or from a hidden step that
+// finish() showed to the last one
+func (m model) back() model {
+	if i := m.position(); i > 0 {
+		m.viewing = m.steps[i-1]
+	} else if i < 0 {
+		m.viewing = m.steps[len(m.steps)-1]
+	}
 	return m
 }
 
+// the commit the collected fields describe, for checking value()
+func (m model) expected() parser.CC {
+	return parser.CC{
+		Type:           m.commit[commitTypeIndex],
+		Scope:          m.commit[scopeIndex],
+		Description:    m.commit[shortDescriptionIndex],
+		BreakingChange: m.breaking,
+	}
+}
+
+// finish after the last step: emit the commit if it's complete, or else
+// return to the first step that needs attention.
+func (m model) finish() (model, tea.Cmd) {
+	if err := m.validateType(); m.ready() && err != nil {
+		m.typeInput = m.typeInput.SetErr(err)
+		m.viewing = commitTypeIndex
+		return m, nil
+	}
+	if err := m.cfg.CheckScopeLength(m.commit[scopeIndex]); m.ready() && err != nil && m.cfg.EnforceMaxLength {
+		m.scopeInput = m.scopeInput.SetErr(err)
+		m.viewing = scopeIndex
+		return m, nil
+	}
+	err := m.cfg.CheckScopeRequired(m.commit[commitTypeIndex], m.commit[scopeIndex])
+	if err == nil {
+		err = m.cfg.CheckScopeAllowed(m.commit[commitTypeIndex], m.commit[scopeIndex])
+	}
+	if m.ready() && err != nil {
+		if m.hidden(scopeIndex) {
+			m.typeInput = m.typeInput.SetErr(err)
+			m.viewing = commitTypeIndex
+		} else {
+			m.scopeInput = m.scopeInput.SetErr(err)
+			m.viewing = scopeIndex
+		}
+		return m, nil
+	}
+	if missing := m.cfg.MissingFooters(m.commit[commitTypeIndex], m.allFooters()); m.ready() && len(missing) > 0 {
+		m.footerInput = footer_input.NewModel(
+			missing[0], config.Tf(config.ErrorFooterRequired, m.commit[commitTypeIndex], missing[0])+":",
+		)
+		m.askingFooter = true
+		return m, nil
+	}
+	if m.ready() && m.cfg.NeedsBreakingChangeDescription(m.breaking, m.allFooters()) {
+		// asked for even if the breaking_change step is hidden, since only it
+		// can hold the explanation
+		m.breakingChangeInput = m.breakingChangeInput.SetErr(config.BreakingChangeDescriptionError())
+		m.viewing = breakingChangeIndex
+		return m, nil
+	}
+	if placeholder := m.cfg.Placeholder(m.commit[shortDescriptionIndex]); m.ready() && placeholder != "" &&
+		(m.cfg.BlockPlaceholderDescriptions || !m.warnedPlaceholder) {
+		m.descriptionInput = m.descriptionInput.SetErr(
+			placeholderErr(placeholder, m.cfg.BlockPlaceholderDescriptions),
+		)
+		m.viewing = shortDescriptionIndex
+		m.warnedPlaceholder = true
+		return m, nil
+	}
+	if err := m.cfg.CheckBodyLength(m.body); m.ready() && err != nil && !m.warnedBodyLength {
+		// the TUI can't edit the body, so it's only a warning
+		m.descriptionInput = m.descriptionInput.SetErr(config.WithHint(err, config.HintSubmitAgain))
+		m.viewing = shortDescriptionIndex
+		m.warnedBodyLength = true
+		return m, nil
+	}
+	if m.ready() && !m.warnedRevert && missingRevertRef(m.commit[commitTypeIndex], m.body, m.allFooters()) {
+		m.descriptionInput = m.descriptionInput.SetErr(config.WithHint(config.Error(config.WarningRevertRef), config.HintSubmitAgain))
+		m.viewing = shortDescriptionIndex
+		m.warnedRevert = true
+		return m, nil
+	}
+	if footers := m.allFooters(); m.ready() && m.cfg.ReviewFooters && m.reviewedFooters == nil && len(footers) > 0 {
+		m.footerReview = footer_review.NewModel(footers, m.canRemoveFooter)
+		m.reviewingFooters = true
+		return m, nil
+	}
+	if m.ready() && m.breaking && m.cfg.ConfirmBreaking && !m.confirmedBreaking {
+		m.confirmingBreaking = true
+		return m, nil
+	}
+	if m.ready() {
+		value := m.value()
+		if m.err = checkRoundTrip(value, m.expected(), m.cfg); m.err != nil {
+			value = "" // abort
+		}
+		m.choice <- value
+		return m, tea.Quit
+	}
+	// TODO: better validation messages
+	if m.commit[commitTypeIndex] == "" {
+		m.viewing = commitTypeIndex
+	} else if m.commit[shortDescriptionIndex] == "" {
+		m.viewing = shortDescriptionIndex
+	}
+	return m, nil
+}
+
+// an error if removing `footer` from the reviewed footers would leave
+// `remaining` without a BREAKING CHANGE footer or a required footer
+func (m model) canRemoveFooter(remaining []string, footer string) error {
+	if parser.IsBreakingChangeFooter(footer) {
+		return config.Error(config.ErrorBreakingFooterRemoval)
+	}
+	if missing := m.cfg.MissingFooters(m.commit[commitTypeIndex], remaining); len(missing) > 0 {
+		return config.MissingFootersError(m.commit[commitTypeIndex], missing)
+	}
+	return nil
+}
+
+// handle a key while reviewing the footers: submitting keeps the reviewed
+// footers and finishes, while going back returns to the last step
+func (m model) reviewFooters(msg tea.KeyMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.Type {
+	case tea.KeyCtrlC, tea.KeyCtrlD:
+		m.choice <- ""
+		return m, tea.Quit
+	case tea.KeyEnter, tea.KeyTab:
+		m.reviewingFooters, m.reviewedFooters = false, m.footerReview.Footers()
+		return m.finish()
+	case tea.KeyShiftTab, tea.KeyEsc:
+		m.reviewingFooters = false
+		m.viewing = m.steps[len(m.steps)-1]
+		return m, cmd
+	}
+	m.footerReview, cmd = m.footerReview.Update(msg)
+	return m, cmd
+}
+
+// an error unless `footer` parses as a footer with `token` and a value
+func checkRequiredFooter(token string, footer string) error {
+	if strings.TrimSpace(strings.TrimPrefix(footer, token+":")) == "" {
+		return errors.New(config.T(config.ErrorRequired))
+	}
+	if !strings.EqualFold(parser.FooterTokenOf(footer), token) {
+		return config.Error(config.ErrorWrongFooter, footer, token)
+	}
+	return nil
+}
+
+// handle a key while asking for a required footer: submitting adds the footer
+// and finishes, while going back returns to the last step
+func (m model) answerFooter(msg tea.KeyMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.Type {
+	case tea.KeyCtrlC, tea.KeyCtrlD:
+		m.choice <- ""
+		return m, tea.Quit
+	case tea.KeyEnter, tea.KeyTab:
+		footer := m.footerInput.Value()
+		if err := checkRequiredFooter(m.footerInput.Token(), footer); err != nil {
+			m.footerInput = m.footerInput.SetErr(err)
+			return m, cmd
+		}
+		m.askingFooter = false
+		m.requiredFooters = append(m.requiredFooters, footer)
+		return m.finish()
+	case tea.KeyShiftTab, tea.KeyEsc:
+		m.askingFooter = false
+		m.viewing = m.steps[len(m.steps)-1]
+		return m, cmd
+	}
+	m.footerInput, cmd = m.footerInput.Update(msg)
+	return m, cmd
+}
+
+// the question confirm_breaking asks before committing a breaking change,
+// including its `explanation` if there is one
+func breakingQuestion(explanation string) string {
+	question := "This commit is marked BREAKING"
+	if explanation != "" {
+		question += ": " + explanation
+	}
+	return question + ". Continue? [y/N]"
+}
+
+// answer breakingQuestion: y submits the commit, while n or esc go back to the
+// breaking-change step to change it
+func (m model) confirmBreaking(msg tea.KeyMsg) (model, tea.Cmd) {
+	answer := strings.ToLower(string(msg.Runes))
+	switch {
+	case msg.Type == tea.KeyCtrlC || msg.Type == tea.KeyCtrlD:
+		m.choice <- ""
+		return m, tea.Quit
+	case msg.Type == tea.KeyRunes && answer == "y":
+		m.confirmingBreaking, m.confirmedBreaking = false, true
+		return m.finish()
+	// [y/N]: enter takes the default, no
+	case msg.Type == tea.KeyEsc || msg.Type == tea.KeyEnter || (msg.Type == tea.KeyRunes && answer == "n"):
+		m.confirmingBreaking, m.reviewedFooters = false, nil
+		if m.hidden(breakingChangeIndex) {
+			m.viewing = shortDescriptionIndex
+		} else {
+			m.viewing = breakingChangeIndex
+		}
+	}
+	return m, nil
+}
+
 func (m model) submit() model {
-	m.commit[m.viewing] = m.currentComponent().Value()
-	m.descriptionInput = m.descriptionInput.SetPrefix(m.contextValue())
+	value := m.currentComponent().Value()
+	switch m.viewing {
+	case commitTypeIndex:
+		m.scopeInput = m.scopeInput.SetType(value)
+		if scope, ok := m.cfg.DefaultScopeByType[value]; ok && m.commit[scopeIndex] == "" {
+			m.scopeInput = m.scopeInput.Highlight(scope)
+		}
+	case scopeIndex:
+		value = config.ApplyScopeCase(m.cfg.ScopeCase, value)
+	case shortDescriptionIndex:
+		value = config.ApplyCase(m.cfg.SubjectCase, value)
+	case breakingChangeIndex:
+		m.breaking = m.breakingChangeInput.Breaking()
+	}
+	m.commit[m.viewing] = value
+	m.descriptionInput = m.descriptionInput.
+		SetPrefix(m.contextValue()).
+		SetSuffix(m.scopeSuffix())
 	return m
 }
 
+// the characters left before the body reaches body_max_length, which are
+// negative once it's over, and whether there's a limit at all.
+func (m model) bodyBudget() (int, bool) {
+	if m.cfg.BodyMaxLength == 0 {
+		return 0, false
+	}
+	return m.cfg.BodyMaxLength - len([]rune(m.body)), true
+}
+
+// route pasted text: the first line goes to the current input and, on the
+// description step, any further lines become the body.
+func (m model) paste(text string) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	lines := strings.SplitN(text, "\n", 2)
+	if lines[0] != "" {
+		m, cmd = m.updateCurrentInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(lines[0])})
+	}
+	if len(lines) > 1 && m.viewing == shortDescriptionIndex {
+		if rest := strings.Trim(lines[1], "\n"); rest != "" {
+			m.body = strings.TrimLeft(m.body+"\n\n"+rest, "\n")
+		}
+	}
+	return m, cmd
+}
+
+// the result of running the description_filter on `original`
+type descriptionFiltered struct {
+	original string
+	filtered string
+	err      error
+}
+
+// run the description_filter on `description` without blocking the UI
+func filterDescription(cfg config.Cfg, description string) tea.Cmd {
+	return func() tea.Msg {
+		filtered, err := cfg.FilterDescription(description)
+		return descriptionFiltered{description, filtered, err}
+	}
+}
+
+// the warning about a failing description_filter
+func filterWarning(err error) error {
+	return config.Error(config.WarningFilterFailed, err)
+}
+
+// submit the description the description_filter returned, or keep the one as
+// typed and warn if it failed
+func (m model) descriptionFiltered(msg descriptionFiltered) (model, tea.Cmd) {
+	m.filtering = false
+	if msg.original != m.descriptionInput.Value() {
+		return m, nil // changed since
+	}
+	m.filterErr = msg.err
+	m.filteredDescription = msg.filtered
+	if msg.err != nil {
+		m.descriptionInput = m.descriptionInput.SetErr(
+			config.WithHint(filterWarning(msg.err), config.HintSubmitToContinue),
+		)
+		return m, nil
+	}
+	m.descriptionInput = m.descriptionInput.SetValue(msg.filtered)
+	if m = m.submit().advance(); m.viewing == nIndices {
+		return m.finish()
+	}
+	return m, nil
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case descriptionFiltered:
+		return m.descriptionFiltered(msg)
 	case tea.KeyMsg:
+		if m.filtering && msg.Type != tea.KeyCtrlC && msg.Type != tea.KeyCtrlD {
+			return m, cmd // wait for the filter
+		}
+		var consumed, done bool
+		var pasted string
+		m.pasting, consumed, pasted, done = m.pasting.feed(msg)
+		if done {
+			return m.paste(pasted)
+		} else if consumed {
+			return m, cmd
+		}
+		if m.askingFooter {
+			return m.answerFooter(msg)
+		}
+		if m.reviewingFooters {
+			return m.reviewFooters(msg)
+		}
+		if m.confirmingBreaking {
+			return m.confirmBreaking(msg)
+		}
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyCtrlD:
 			m.choice <- ""
 			return m, tea.Quit
 		case tea.KeyShiftTab:
-			if m.viewing > commitTypeIndex {
-				m.viewing--
-			}
-			return m, cmd
+			return m.back(), cmd
 		case tea.KeyEnter, tea.KeyTab:
 			switch m.viewing {
 			default:
 				m = m.submit().advance()
 			case commitTypeIndex:
-				if m.currentComponent().Value() == "" {
+				if strings.TrimSpace(m.currentComponent().Value()) == "" {
+					m.typeInput = m.typeInput.SetErr(errors.New(config.T(config.ErrorRequired)))
 					return m, cmd
 				} else {
 					m = m.submit().advance()
@@ -212,27 +715,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.currentComponent().Value() == "new scope" {
 					m.scopeInput, cmd = m.scopeInput.Update(msg)
 					return m, cmd
+				} else if err := m.cfg.CheckScopeLength(m.currentComponent().Value()); err != nil && m.cfg.EnforceMaxLength {
+					m.scopeInput = m.scopeInput.SetErr(err)
+					return m, cmd
+				} else if err := m.cfg.CheckScopeRequired(m.commit[commitTypeIndex], m.currentComponent().Value()); err != nil {
+					m.scopeInput = m.scopeInput.SetErr(err)
+					return m, cmd
+				} else if err := m.cfg.CheckScopeAllowed(m.commit[commitTypeIndex], m.currentComponent().Value()); err != nil {
+					m.scopeInput = m.scopeInput.SetErr(err)
+					return m, cmd
 				} else {
 					m = m.submit().advance()
 				}
+			case shortDescriptionIndex:
+				if value := m.currentComponent().Value(); m.cfg.DescriptionFilter != "" && value != m.filteredDescription {
+					m.filtering = true
+					return m, filterDescription(m.cfg, value)
+				}
+				m = m.submit().advance()
 			case breakingChangeIndex:
-				m = m.submit()
-				if m.ready() {
-					m.choice <- m.value()
-					return m, tea.Quit
-				} else {
-					// TODO: better validation messages
-					if m.commit[commitTypeIndex] == "" {
-						m.viewing = commitTypeIndex
-					} else if m.commit[shortDescriptionIndex] == "" {
-						m.viewing = shortDescriptionIndex
-					}
+				var done bool
+				m.breakingChangeInput, done = m.breakingChangeInput.Confirm()
+				if !done { // ask for an explanation
 					return m, cmd
 				}
+				m = m.submit().advance()
+			}
+			if m.viewing == nIndices {
+				return m.finish()
 			}
 			return m, cmd
 		default:
+			declined := m.viewing == breakingChangeIndex && m.breakingChangeInput.Declines(msg)
 			m, cmd = m.updateCurrentInput(msg)
+			if declined {
+				if m = m.submit().advance(); m.viewing == nIndices {
+					return m.finish()
+				}
+			}
 		}
 	case tea.WindowSizeMsg:
 		// ensure instances of tea.WindowSizeMsg reach all child-components
@@ -240,6 +760,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.scopeInput, _ = m.scopeInput.Update(msg)
 		m.descriptionInput, _ = m.descriptionInput.Update(msg)
 		m.breakingChangeInput, cmd = m.breakingChangeInput.Update(msg)
+		m.footerReview, _ = m.footerReview.Update(msg)
+		m.footerInput, _ = m.footerInput.Update(msg)
 	default:
 		m, cmd = m.updateCurrentInput(msg)
 	}
@@ -247,5 +769,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
-	return m.currentComponent().View() + "\n"
+	if m.err != nil {
+		return m.err.Error() + "\n"
+	}
+	if m.askingFooter {
+		return m.footerInput.View()
+	}
+	if m.reviewingFooters {
+		return m.footerReview.View()
+	}
+	if m.confirmingBreaking {
+		return breakingQuestion(strings.TrimSpace(m.commit[breakingChangeIndex])) + "\n"
+	}
+	if m.viewing == nIndices {
+		return "" // done
+	}
+	view := m.currentComponent().View() + "\n"
+	if m.filtering {
+		view += config.Faint(config.T(config.StatusFiltering)) + "\n"
+	}
+	if m.viewing == shortDescriptionIndex && m.body != "" {
+		status := fmt.Sprintf("body: %d line(s)", strings.Count(m.body, "\n")+1)
+		if remaining, limited := m.bodyBudget(); limited && remaining < 0 {
+			status += config.Underline(", " + config.Tf(config.StatusOverLimit, -remaining))
+		} else if limited {
+			status += fmt.Sprintf(", %d character(s) left", remaining)
+		}
+		view += config.Faint(status) + "\n"
+	}
+	return view
 }
@@ -0,0 +1,781 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+var testCfg = config.Cfg{
+	CommitTypes:        []map[string]string{{"feat": "adds a feature"}, {"feat fix": "malformed"}},
+	Scopes:             []map[string]string{{"cli": "the cli"}},
+	HeaderMaxLength:    72,
+	BreakingChangeBang: true,
+}
+
+func press(m model, keys ...tea.KeyType) model {
+	for _, key := range keys {
+		next, cmd := m.Update(tea.KeyMsg{Type: key})
+		m = next.(model)
+		if m.filtering && cmd != nil { // wait for the description_filter
+			next, _ = m.Update(cmd())
+			m = next.(model)
+		}
+	}
+	return m
+}
+
+func TestRejectingMultipleTypes(t *testing.T) {
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat fix", Description: "x"}, testCfg)
+	m = press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // scope, description, breaking change
+	if m.viewing != commitTypeIndex {
+		t.Errorf("expected to return to the type step, not %d", m.viewing)
+	}
+	if len(choice) != 0 {
+		t.Errorf("expected no submission, got %q", <-choice)
+	}
+}
+
+func TestAcceptingASingleType(t *testing.T) {
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Description: "x"}, testCfg)
+	press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // scope, description, breaking change
+	if len(choice) != 1 {
+		t.Fatal("expected a submission")
+	}
+	if result := <-choice; result != "feat: x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestSentenceCasingTheDescription(t *testing.T) {
+	choice := make(chan string, 1)
+	cfg := testCfg
+	cfg.SubjectCase = config.CaseSentence
+	m := initialModel(choice, &parser.CC{Type: "feat", Scope: "cli", Description: "élan"}, cfg)
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != "feat(cli): Élan\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestLowerCasingTheScope(t *testing.T) {
+	choice := make(chan string, 1)
+	cfg := testCfg
+	cfg.ScopeCase = config.CaseLower
+	cfg.Scopes = []map[string]string{{"Auth": "mixed-case scope"}}
+	m := initialModel(choice, &parser.CC{Type: "feat", Description: "Add"}, cfg)
+	m = press(m, tea.KeyDown) // "" -> Auth
+	press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter)
+	if result := <-choice; result != "feat(auth): Add\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestAppendingDefaultFooters(t *testing.T) {
+	cfg := testCfg
+	cfg.DefaultFooters = []string{"Change-type: patch", "Refs: none"}
+	cfg.DefaultFootersByType = map[string][]string{"fix": {"Change-type: fix"}}
+	test := func(cc *parser.CC, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			choice := make(chan string, 1)
+			m := initialModel(choice, cc, cfg)
+			press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter)
+			if result := <-choice; result != expected {
+				t.Errorf("expected %q, got %q", expected, result)
+			}
+		}
+	}
+	t.Run("after user-entered footers", test(
+		&parser.CC{Type: "feat", Description: "x", Footers: []string{"Reviewed-by: Z"}},
+		"feat: x\n\nReviewed-by: Z\nChange-type: patch\nRefs: none\n",
+	))
+	t.Run("without duplicating already-tagged footers", test(
+		&parser.CC{Type: "feat", Description: "x", Footers: []string{"Change-type: minor"}},
+		"feat: x\n\nChange-type: minor\nRefs: none\n",
+	))
+}
+
+func TestDefaultFootersByType(t *testing.T) {
+	cfg := testCfg
+	cfg.DefaultFooters = []string{"Change-type: patch"}
+	cfg.DefaultFootersByType = map[string][]string{"feat": {"Change-type: minor"}}
+	if footers := cfg.FootersFor("feat"); fmt.Sprint(footers) != "[Change-type: minor]" {
+		t.Errorf("expected the per-type override, got %q", footers)
+	}
+	if footers := cfg.FootersFor("fix"); fmt.Sprint(footers) != "[Change-type: patch]" {
+		t.Errorf("expected the default footers, got %q", footers)
+	}
+}
+
+func TestEmittingFooterValues(t *testing.T) {
+	test := func(mode string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			cfg := testCfg
+			cfg.FooterValues = mode
+			choice := make(chan string, 1)
+			cc := &parser.CC{Type: "feat", Description: "x", Footers: []string{"Refs:   #1"}}
+			press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter, tea.KeyEnter)
+			if result := <-choice; result != expected {
+				t.Errorf("expected %q, got %q", expected, result)
+			}
+		}
+	}
+	t.Run("trimmed", test(config.FooterValuesTrim, "feat: x\n\nRefs: #1\n"))
+	t.Run("verbatim", test(config.FooterValuesVerbatim, "feat: x\n\nRefs:   #1\n"))
+}
+
+func TestWritingTheScopeLast(t *testing.T) {
+	choice := make(chan string, 1)
+	cfg := testCfg
+	cfg.ScopePosition = config.ScopeSuffix
+	m := initialModel(choice, &parser.CC{Type: "feat", Scope: "cli", Description: "x"}, cfg)
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != "feat: x (cli)\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestMinimalMode(t *testing.T) {
+	cfg := testCfg
+	cfg.Minimal = true
+	cfg.DefaultFooters = []string{"Change-type: patch"}
+	choice := make(chan string, 1)
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "x", Footers: []string{"Refs: #1"}}
+	m := initialModel(choice, cc, cfg)
+	if m.viewing != shortDescriptionIndex {
+		t.Fatalf("expected to start at the description, got step %d", m.viewing)
+	}
+	m = press(m, tea.KeyShiftTab)
+	if m.viewing != commitTypeIndex {
+		t.Fatalf("going back should skip the scope, got step %d", m.viewing)
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter) // type, description
+	if result := <-choice; result != "feat: x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestViewingAfterCommitting(t *testing.T) {
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Description: "x"}, testCfg)
+	m = press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // scope, description, breaking change
+	if len(choice) != 1 {
+		t.Fatal("expected a submission")
+	}
+	if view := m.View(); view != "" {
+		t.Errorf("expected an empty view once done, got %q", view)
+	}
+}
+
+func TestReorderingSteps(t *testing.T) {
+	cfg := testCfg
+	cfg.Steps = []string{config.StepDescription, config.StepCommitType}
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Description: "x"}, cfg)
+	if m.viewing != shortDescriptionIndex {
+		t.Fatalf("expected to start at the description, got step %d", m.viewing)
+	}
+	m = press(m, tea.KeyEnter)
+	if m.viewing != commitTypeIndex {
+		t.Fatalf("expected the commit type after the description, got step %d", m.viewing)
+	}
+	m = press(m, tea.KeyShiftTab)
+	if m.viewing != shortDescriptionIndex {
+		t.Fatalf("expected to go back to the description, got step %d", m.viewing)
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, type
+	if result := <-choice; result != "feat: x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestPreselectingTheDefaultScope(t *testing.T) {
+	cfg := testCfg
+	cfg.Scopes = []map[string]string{{"cli": "the cli"}, {"deps": "dependencies"}}
+	cfg.DefaultScopeByType = map[string]string{"feat": "deps"}
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Description: "x"}, cfg)
+	m = press(m, tea.KeyEnter) // type
+	if value := m.scopeInput.Value(); value != "deps" {
+		t.Errorf("expected the default scope to be highlighted, got %q", value)
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // scope, description, breaking change
+	if result := <-choice; result != "feat(deps): x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+
+	m = initialModel(choice, &parser.CC{Description: "x"}, cfg)
+	m = press(m, tea.KeyEnter, tea.KeyUp) // type, deps -> cli
+	press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter)
+	if result := <-choice; result != "feat(cli): x\n" {
+		t.Errorf("expected the default scope to be changeable, got %q", result)
+	}
+}
+
+func TestPreselectingTheDefaultScopeOfAGivenType(t *testing.T) {
+	cfg := testCfg
+	cfg.Scopes = []map[string]string{{"cli": "the cli"}, {"deps": "dependencies"}}
+	cfg.DefaultScopeByType = map[string]string{"feat": "deps"}
+	cfg.Steps = []string{config.StepScope, config.StepCommitType, config.StepDescription}
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Description: "x"}, cfg)
+	if m.viewing != scopeIndex || m.scopeInput.Value() != "deps" {
+		t.Fatalf("expected the default scope to be highlighted, got %q at step %d", m.scopeInput.Value(), m.viewing)
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter) // scope, description
+	if result := <-choice; result != "feat(deps): x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+	m = initialModel(choice, &parser.CC{Type: "feat", Scope: "cli", Description: "x"}, cfg)
+	if value := m.scopeInput.Value(); value != "cli" {
+		t.Errorf("expected a given scope to win over the default, got %q", value)
+	}
+}
+
+func TestLimitingTheScopeLength(t *testing.T) {
+	cfg := testCfg
+	cfg.Scopes = []map[string]string{{"parse": ""}, {"parser": ""}}
+	cfg.ScopeMaxLength = 5
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Description: "x"}, cfg)
+	m = press(m, tea.KeyDown) // "" -> parse
+	if view := m.View(); !strings.Contains(view, "(scope 5/5, 59 left for the description)") {
+		t.Errorf("expected a counter, got:\n%s", view)
+	}
+	m = press(m, tea.KeyDown) // parse -> parser
+	m = press(m, tea.KeyEnter)
+	if m.viewing != shortDescriptionIndex {
+		t.Errorf("expected long scopes to only be warned about, got step %d", m.viewing)
+	}
+
+	cfg.EnforceMaxLength = true
+	m = initialModel(choice, &parser.CC{Type: "feat", Description: "x"}, cfg)
+	m = press(m, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	if m.viewing != scopeIndex || !strings.Contains(m.View(), "the limit is 5") {
+		t.Errorf("expected an enforced limit to refuse the scope, got:\n%s", m.View())
+	}
+	press(m, tea.KeyUp, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // parse
+	if result := <-choice; result != "feat(parse): x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestMarkingBreakingChanges(t *testing.T) {
+	test := func(cc *parser.CC, bang bool, keys []tea.KeyMsg, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			cfg := testCfg
+			cfg.BreakingChangeBang = bang
+			choice := make(chan string, 1)
+			m := press(initialModel(choice, cc, cfg), tea.KeyEnter) // description
+			for _, key := range keys {
+				next, _ := m.Update(key)
+				m = next.(model)
+			}
+			if result := <-choice; result != expected {
+				t.Errorf("expected %q, got %q", expected, result)
+			}
+		}
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	yes := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}
+	explain := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("drops y")}
+	newCommit := func() *parser.CC { return &parser.CC{Type: "feat", Scope: "cli", Description: "x"} }
+	t.Run("no", test(newCommit(), true, []tea.KeyMsg{enter}, "feat(cli): x\n"))
+	t.Run("yes, without an explanation", test(
+		newCommit(), false, []tea.KeyMsg{yes, enter, enter}, "feat(cli)!: x\n",
+	))
+	t.Run("yes, with an explanation", test(
+		newCommit(), true, []tea.KeyMsg{yes, enter, explain, enter},
+		"feat(cli)!: x\n\nBREAKING CHANGE: drops y\n",
+	))
+	t.Run("yes, with an explanation and no bang", test(
+		newCommit(), false, []tea.KeyMsg{yes, enter, explain, enter},
+		"feat(cli): x\n\nBREAKING CHANGE: drops y\n",
+	))
+	t.Run("a stray space isn't breaking", test(
+		newCommit(), true, []tea.KeyMsg{{Type: tea.KeySpace, Runes: []rune(" ")}, enter},
+		"feat(cli): x\n",
+	))
+	t.Run("an existing bang", test(
+		&parser.CC{Type: "feat", Scope: "cli", Description: "x", Bang: true}, true,
+		[]tea.KeyMsg{enter, enter}, "feat(cli)!: x\n",
+	))
+	t.Run("an existing footer", test(
+		&parser.CC{Type: "feat", Scope: "cli", Description: "x", Footers: []string{"BREAKING CHANGE: y"}}, false,
+		[]tea.KeyMsg{enter, enter}, "feat(cli): x\n\nBREAKING CHANGE: y\n",
+	))
+	t.Run("answering no to an existing footer", test(
+		&parser.CC{Type: "feat", Scope: "cli", Description: "x", Footers: []string{"BREAKING CHANGE: y"}}, true,
+		[]tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("n")}}, "feat(cli): x\n",
+	))
+	t.Run("n skips the explanation", test(
+		newCommit(), true, []tea.KeyMsg{yes, {Type: tea.KeyRunes, Runes: []rune("N")}}, "feat(cli): x\n",
+	))
+}
+
+func TestDecliningBreakingChangesBeforeOtherSteps(t *testing.T) {
+	cfg := testCfg
+	cfg.Steps = []string{config.StepCommitType, config.StepBreakingChange, config.StepDescription}
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Type: "feat", Scope: "cli", Bang: true}, cfg)
+	if m.viewing != breakingChangeIndex {
+		t.Fatalf("expected to start at the breaking change, got step %d", m.viewing)
+	}
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("n")},
+		{Type: tea.KeyRunes, Runes: []rune("x")},
+	} {
+		next, _ := m.Update(key)
+		m = next.(model)
+	}
+	if m.viewing != shortDescriptionIndex || m.breaking {
+		t.Fatalf("expected n to record no breaking change and advance, got step %d", m.viewing)
+	}
+	press(m, tea.KeyEnter) // description
+	if result := <-choice; result != "feat(cli): x\n" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestRequiringBreakingChangeDescriptions(t *testing.T) {
+	cfg := testCfg
+	cfg.RequireBreakingChangeDescription = true
+	choice := make(chan string, 1)
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "x", Bang: true}
+	m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // description, yes, no explanation
+	if m.viewing != breakingChangeIndex || !strings.Contains(m.View(), "BREAKING CHANGE") {
+		t.Fatalf("expected to be asked for an explanation, got step %d:\n%s", m.viewing, m.View())
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("drops y")})
+	press(next.(model), tea.KeyEnter)
+	expected := "feat(cli)!: x\n\nBREAKING CHANGE: drops y\n"
+	if result := <-choice; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+
+	// without the breaking_change step, its explanation is still asked for
+	cfg.Steps = []string{config.StepCommitType, config.StepScope, config.StepDescription}
+	m = press(initialModel(choice, cc, cfg), tea.KeyEnter) // description
+	if m.viewing != breakingChangeIndex || !strings.Contains(m.View(), "BREAKING CHANGE") {
+		t.Fatalf("expected to be asked for an explanation, got step %d:\n%s", m.viewing, m.View())
+	}
+	if back := press(m, tea.KeyShiftTab); back.viewing != shortDescriptionIndex {
+		t.Errorf("expected going back to return to the description, got step %d", back.viewing)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("drops y")})
+	press(next.(model), tea.KeyEnter)
+	if result := <-choice; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestConfirmingBreakingChanges(t *testing.T) {
+	cfg := testCfg
+	cfg.ConfirmBreaking = true
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "x", Footers: []string{"BREAKING CHANGE: drops y"}}
+	start := func(choice chan string) model {
+		m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // description, yes, explanation
+		if !m.confirmingBreaking || !strings.Contains(m.View(), "marked BREAKING: drops y. Continue?") {
+			t.Fatalf("expected to be asked to confirm, got:\n%s", m.View())
+		}
+		return m
+	}
+	t.Run("confirmed", func(t *testing.T) {
+		choice := make(chan string, 1)
+		start(choice).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		if result := <-choice; result != "feat(cli)!: x\n\nBREAKING CHANGE: drops y\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	for name, key := range map[string]tea.KeyMsg{
+		"cancelled":            {Type: tea.KeyRunes, Runes: []rune("n")},
+		"cancelled by default": {Type: tea.KeyEnter},
+	} {
+		t.Run(name, func(t *testing.T) {
+			choice := make(chan string, 1)
+			next, _ := start(choice).Update(key)
+			m := next.(model)
+			if m.confirmingBreaking || m.viewing != breakingChangeIndex {
+				t.Errorf("expected to return to the breaking-change step, got step %d", m.viewing)
+			}
+			select {
+			case result := <-choice:
+				t.Errorf("expected nothing to be submitted, got %q", result)
+			default:
+			}
+		})
+	}
+	t.Run("not breaking", func(t *testing.T) {
+		choice := make(chan string, 1)
+		press(initialModel(choice, &parser.CC{Type: "feat", Scope: "cli", Description: "x"}, cfg), tea.KeyEnter, tea.KeyEnter)
+		if result := <-choice; result != "feat(cli): x\n" {
+			t.Errorf("expected no confirmation, got %q", result)
+		}
+	})
+}
+
+func TestReviewingFooters(t *testing.T) {
+	cfg := testCfg
+	cfg.ReviewFooters = true
+	cfg.DefaultFooters = []string{"Reviewed-by: Z"}
+	cfg.RequiredFooters = map[string][]string{"feat": {"Refs"}}
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "x", Footers: []string{"Refs: #1", "Acked-by: Y"}}
+	key := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	review := func(choice chan string, keys ...tea.KeyMsg) model {
+		m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter) // description, breaking change
+		if !m.reviewingFooters || !strings.Contains(m.View(), "Acked-by: Y") {
+			t.Fatalf("expected to review the footers, got:\n%s", m.View())
+		}
+		for _, msg := range keys {
+			next, _ := m.Update(msg)
+			m = next.(model)
+		}
+		return m
+	}
+	t.Run("removing", func(t *testing.T) {
+		choice := make(chan string, 1)
+		m := review(choice, tea.KeyMsg{Type: tea.KeyDown}, key("d"))
+		if m.value() != "feat(cli): x\n\nAcked-by: Y\nRefs: #1\n" {
+			t.Errorf("expected the removal in the message, got %q", m.value())
+		}
+		press(m, tea.KeyEnter)
+		if result := <-choice; result != "feat(cli): x\n\nAcked-by: Y\nRefs: #1\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	t.Run("reordering", func(t *testing.T) {
+		choice := make(chan string, 1)
+		press(review(choice, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyShiftUp}), tea.KeyEnter)
+		if result := <-choice; result != "feat(cli): x\n\nAcked-by: Y\nRefs: #1\nReviewed-by: Z\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	t.Run("required footers stay", func(t *testing.T) {
+		m := review(make(chan string, 1), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, key("d"))
+		if !strings.Contains(m.View(), "'feat' commits need a Refs footer") || !strings.Contains(m.value(), "Refs: #1") {
+			t.Errorf("expected the required footer to stay, got:\n%s", m.View())
+		}
+	})
+}
+
+func TestCommittingTypesShownByLabel(t *testing.T) {
+	cfg := testCfg
+	cfg.TypeLabels = map[string]string{"feat": "✨ feature"}
+	choice := make(chan string, 1)
+	m := initialModel(choice, &parser.CC{Scope: "cli", Description: "x"}, cfg)
+	if !strings.Contains(m.View(), "✨ feature") {
+		t.Fatalf("expected the label, got:\n%s", m.View())
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // type, description, breaking change
+	if result := <-choice; result != "feat(cli): x\n" {
+		t.Errorf("expected the type to be committed, got %q", result)
+	}
+}
+
+func TestDescribingTheBreakingChangeKeys(t *testing.T) {
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "x"}
+	view := press(initialModel(make(chan string, 1), cc, testCfg), tea.KeyEnter).View()
+	for _, help := range []string{"toggle: left/right", "breaking: y", "not breaking: n"} {
+		if !strings.Contains(view, help) {
+			t.Errorf("expected %q in the help, got:\n%s", help, view)
+		}
+	}
+}
+
+func TestBreakingChangeTemplate(t *testing.T) {
+	cfg := testCfg
+	cfg.BreakingChangeTemplate = "migrate X to Y"
+	choice := make(chan string, 1)
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "x"}
+	m := press(initialModel(choice, cc, cfg), tea.KeyEnter) // description
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("y")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune(" first")},
+		{Type: tea.KeyEnter},
+	} {
+		next, _ := m.Update(key)
+		m = next.(model)
+	}
+	expected := "feat(cli)!: x\n\nBREAKING CHANGE: migrate X to Y first\n"
+	result := <-choice
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+	if parsed, _ := parser.ParseAsMuchOfCCAsPossible(result); !parsed.BreakingChange {
+		t.Errorf("expected %q to parse as a breaking change", result)
+	}
+}
+
+func TestFilteringTheDescription(t *testing.T) {
+	cfg := testCfg
+	cfg.DescriptionFilter = "tr a-z A-Z"
+	choice := make(chan string, 1)
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "shout"}
+	press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != "feat(cli): SHOUT\n" {
+		t.Errorf("expected the filtered description, got %q", result)
+	}
+
+	cfg.DescriptionFilter = "exit 3"
+	m := initialModel(choice, cc, cfg)
+	next, filter := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if !m.filtering || filter == nil || !strings.Contains(m.View(), "running the description_filter") {
+		t.Fatalf("expected the filter to run as a command, got:\n%s", m.View())
+	}
+	if m = press(m, tea.KeyRunes); m.descriptionInput.Value() != "shout" {
+		t.Errorf("expected keys to wait for the filter, got %q", m.descriptionInput.Value())
+	}
+	next, _ = m.Update(filter())
+	m = next.(model)
+	if m.viewing != shortDescriptionIndex || !strings.Contains(m.View(), "description_filter failed") {
+		t.Fatalf("expected a warning about the filter, got:\n%s", m.View())
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != "feat(cli): shout\n" {
+		t.Errorf("expected submitting again to keep the description, got %q", result)
+	}
+}
+
+func TestWarningAboutUnreferencedReverts(t *testing.T) {
+	cfg := testCfg
+	cfg.CommitTypes = []map[string]string{{"revert": ""}}
+	choice := make(chan string, 1)
+	cc := &parser.CC{Type: "revert", Scope: "cli", Description: "x"}
+	m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if m.viewing != shortDescriptionIndex || !strings.Contains(m.View(), "submit again") {
+		t.Fatalf("expected a warning at the description, got:\n%s", m.View())
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter)
+	if result := <-choice; result != "revert(cli): x\n" {
+		t.Errorf("expected submitting again to commit anyway, got %q", result)
+	}
+}
+
+func TestFlaggingPlaceholderDescriptions(t *testing.T) {
+	cfg := testCfg
+	cfg.PlaceholderDescriptions = config.DefaultPlaceholderDescriptions
+	choice := make(chan string, 1)
+	cc := &parser.CC{Type: "feat", Scope: "cli", Description: "WIP parser"}
+	m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if m.viewing != shortDescriptionIndex || !strings.Contains(m.View(), "placeholder") {
+		t.Fatalf("expected a warning at the description, got:\n%s", m.View())
+	}
+	press(m, tea.KeyEnter, tea.KeyEnter)
+	if result := <-choice; result != "feat(cli): WIP parser\n" {
+		t.Errorf("expected submitting again to commit anyway, got %q", result)
+	}
+
+	cfg.BlockPlaceholderDescriptions = true
+	m = press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter)
+	m = press(m, tea.KeyEnter, tea.KeyEnter)
+	if m.viewing != shortDescriptionIndex || len(choice) != 0 {
+		t.Errorf("expected placeholders to be refused, got step %d", m.viewing)
+	}
+}
+
+func TestRequiringFootersByType(t *testing.T) {
+	cfg := testCfg
+	cfg.CommitTypes = []map[string]string{{"feat": ""}, {"fix": ""}}
+	cfg.RequiredFooters = map[string][]string{"fix": {"Refs"}}
+	test := func(cc *parser.CC, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			choice := make(chan string, 1)
+			press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter) // description, breaking change
+			if result := <-choice; result != expected {
+				t.Errorf("expected %q, got %q", expected, result)
+			}
+		}
+	}
+	t.Run("feat doesn't need a footer", test(
+		&parser.CC{Type: "feat", Scope: "cli", Description: "x"}, "feat(cli): x\n",
+	))
+	t.Run("fix with one", test(
+		&parser.CC{Type: "fix", Scope: "cli", Description: "x", Footers: []string{"refs: #1"}},
+		"fix(cli): x\n\nrefs: #1\n",
+	))
+	t.Run("fix asks for one", func(t *testing.T) {
+		choice := make(chan string, 1)
+		cc := &parser.CC{Type: "fix", Scope: "cli", Description: "x"}
+		m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter)
+		if !m.askingFooter || !strings.Contains(m.View(), "'fix' commits need a Refs footer") {
+			t.Fatalf("expected to be asked for the footer, got:\n%s", m.View())
+		}
+		m = press(m, tea.KeyEnter)
+		if len(choice) != 0 || !strings.Contains(m.View(), config.T(config.ErrorRequired)) {
+			t.Fatalf("expected an empty footer to be refused, got:\n%s", m.View())
+		}
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#1")})
+		press(next.(model), tea.KeyEnter)
+		if result := <-choice; result != "fix(cli): x\n\nRefs: #1\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	t.Run("going back", func(t *testing.T) {
+		choice := make(chan string, 1)
+		cc := &parser.CC{Type: "fix", Scope: "cli", Description: "x"}
+		m := press(initialModel(choice, cc, cfg), tea.KeyEnter, tea.KeyEnter, tea.KeyEsc)
+		if m.askingFooter || m.viewing != breakingChangeIndex {
+			t.Errorf("expected to return to the last step, got step %d", m.viewing)
+		}
+	})
+}
+
+func TestRequiringScopesByType(t *testing.T) {
+	cfg := testCfg
+	cfg.CommitTypes = []map[string]string{{"feat": ""}, {"fix": ""}}
+	cfg.RequireScopeFor = []string{"fix"}
+	t.Run("feat may be unscoped", func(t *testing.T) {
+		choice := make(chan string, 1)
+		m := initialModel(choice, &parser.CC{Type: "feat", Description: "x"}, cfg)
+		if m.viewing != scopeIndex {
+			t.Fatalf("expected to start at the scope step, got step %d", m.viewing)
+		}
+		press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // unscoped, description, breaking change
+		if result := <-choice; result != "feat: x\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	t.Run("fix is blocked without a scope", func(t *testing.T) {
+		choice := make(chan string, 1)
+		m := press(initialModel(choice, &parser.CC{Type: "fix", Description: "x"}, cfg), tea.KeyEnter)
+		if m.viewing != scopeIndex || !strings.Contains(m.View(), "'fix' commits need a scope") {
+			t.Fatalf("expected to stay on the scope step with an error, got:\n%s", m.View())
+		}
+		press(m, tea.KeyDown, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // cli, description, breaking change
+		if result := <-choice; result != "fix(cli): x\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	t.Run("a given scope still skips the scope step", func(t *testing.T) {
+		m := initialModel(make(chan string, 1), &parser.CC{Type: "fix", Scope: "cli", Description: "x"}, cfg)
+		if m.viewing != shortDescriptionIndex {
+			t.Errorf("expected to skip to the description, got step %d", m.viewing)
+		}
+	})
+	t.Run("without a scope step, fix is sent back to the type", func(t *testing.T) {
+		minimal := cfg
+		minimal.Minimal = true
+		choice := make(chan string, 1)
+		m := press(initialModel(choice, &parser.CC{Type: "fix", Description: "x"}, minimal), tea.KeyEnter)
+		if m.viewing != commitTypeIndex || !strings.Contains(m.View(), "need a scope") {
+			t.Errorf("expected to be sent back to the type with an error, got:\n%s", m.View())
+		}
+	})
+}
+
+func TestNarrowingScopesByType(t *testing.T) {
+	cfg := testCfg
+	cfg.CommitTypes = []map[string]string{{"feat": ""}, {"fix": ""}}
+	cfg.Scopes = []map[string]string{{"api": "the api"}, {"cli": "the cli"}}
+	cfg.RequireScopeFor = []string{"fix"}
+	cfg.ScopesByType = map[string][]string{"fix": {"cli"}}
+	t.Run("fix only offers its scopes", func(t *testing.T) {
+		choice := make(chan string, 1)
+		m := press(initialModel(choice, &parser.CC{Description: "x"}, cfg), tea.KeyDown, tea.KeyEnter)
+		if view := m.View(); m.viewing != scopeIndex || strings.Contains(view, "the api") || !strings.Contains(view, "the cli") {
+			t.Fatalf("expected only fix's scopes, got:\n%s", view)
+		}
+		m = press(m, tea.KeyEnter) // unscoped
+		if !strings.Contains(m.View(), "'fix' commits need a scope") {
+			t.Fatalf("expected fix to still need a scope, got:\n%s", m.View())
+		}
+		press(m, tea.KeyDown, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // cli, description, breaking change
+		if result := <-choice; result != "fix(cli): x\n" {
+			t.Errorf("unexpected result %q", result)
+		}
+	})
+	t.Run("changing the type restores the other scopes", func(t *testing.T) {
+		m := press(initialModel(make(chan string, 1), &parser.CC{Description: "x"}, cfg),
+			tea.KeyDown, tea.KeyEnter, tea.KeyShiftTab, tea.KeyUp, tea.KeyEnter) // fix, back, feat
+		if view := m.View(); m.viewing != scopeIndex || !strings.Contains(view, "the api") {
+			t.Errorf("expected every scope for feat, got:\n%s", view)
+		}
+	})
+	t.Run("a given scope it doesn't allow is refused", func(t *testing.T) {
+		withoutScopes := cfg
+		withoutScopes.Steps = []string{config.StepCommitType, config.StepDescription}
+		cc := &parser.CC{Type: "fix", Scope: "api", Description: "x"}
+		m := press(initialModel(make(chan string, 1), cc, withoutScopes), tea.KeyEnter)
+		if m.viewing != commitTypeIndex || !strings.Contains(m.View(), "'fix' commits can't have the scope 'api'") {
+			t.Errorf("expected to be sent back to the type with an error, got:\n%s", m.View())
+		}
+	})
+}
+
+func TestKeepingASignatureBlock(t *testing.T) {
+	message := "feat(cli): x\n\nRefs: #1\n\n-- \nJane Doe\n"
+	cc, _ := parser.ParseAsMuchOfCCAsPossible(message)
+	choice := make(chan string, 1)
+	press(initialModel(choice, cc, testCfg), tea.KeyEnter, tea.KeyEnter) // description, breaking change
+	if result := <-choice; result != message {
+		t.Errorf("expected the signature to be kept, got %q", result)
+	}
+}
+
+func TestAbortingMessagesThatDontRoundTrip(t *testing.T) {
+	cfg := testCfg
+	cfg.ScopePosition = config.ScopeSuffix
+	choice := make(chan string, 1)
+	// an unscoped description that looks like it ends with a scope
+	m := initialModel(choice, &parser.CC{Type: "feat", Description: "support x (beta)"}, cfg)
+	m = press(m, tea.KeyEnter, tea.KeyEnter, tea.KeyEnter) // scope, description, breaking change
+	if result := <-choice; result != "" {
+		t.Errorf("expected the commit to be aborted, got %q", result)
+	}
+	if m.err == nil || !strings.Contains(m.View(), "scope") {
+		t.Errorf("expected a diagnostic about the scope, got %q", m.View())
+	}
+	if err := checkRoundTrip("feat(cli)!: x\n", parser.CC{
+		Type: "feat", Scope: "cli", Description: "x ", BreakingChange: true,
+	}, testCfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRequiringAType(t *testing.T) {
+	m := initialModel(make(chan string, 1), &parser.CC{}, testCfg)
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")}) // matches nothing
+	m = press(next.(model), tea.KeyEnter)
+	if m.viewing != commitTypeIndex {
+		t.Fatalf("expected to stay on the type step, got step %d", m.viewing)
+	}
+	if !strings.Contains(m.View(), "required") {
+		t.Errorf("expected a required error, got %q", m.View())
+	}
+}
+
+func TestRenderingOnDumbTerminals(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+	defer func(profile, bubbles termenv.Profile) {
+		config.ColorProfile = profile
+		lipgloss.SetColorProfile(bubbles)
+	}(config.ColorProfile, lipgloss.ColorProfile())
+	config.SetColorProfile(config.DetectColorProfile())
+	check := func(view string) {
+		for _, r := range view {
+			if r == '\x1b' || r > unicode.MaxASCII {
+				t.Errorf("expected plain ASCII, got %q in:\n%s", r, view)
+				return
+			}
+		}
+	}
+	choice := make(chan string, 1)
+	check(initialModel(choice, &parser.CC{}, testCfg).View()) // type
+	m := initialModel(choice, &parser.CC{Type: "feat"}, testCfg)
+	check(m.View()) // scope
+	m = press(m, tea.KeyEnter)
+	check(m.View()) // description
+	m = press(m, tea.KeyRunes, tea.KeyEnter)
+	check(m.View()) // breaking change
+}
@@ -6,41 +6,128 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/muesli/termenv"
 	"github.com/skalt/git-cc/pkg/config"
 	"github.com/skalt/git-cc/pkg/helpbar"
 )
 
+// asks whether the commit is a breaking change, then only if so asks for an
+// explanation.
 type Model struct {
-	input   textinput.Model
-	helpBar helpbar.Model
+	input      textinput.Model
+	helpBar    helpbar.Model
+	breaking   bool
+	explaining bool // whether the yes/no question has been answered "yes"
+	question   string
 }
 
-var helpBar = termenv.String(strings.Join(
-	[]string{config.HelpSubmit, config.HelpBack, config.HelpCancel}, "; "),
-).Faint().String()
+// join the translations of `keys` into a faint help line
+func helpLine(keys ...string) string {
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = config.T(key)
+	}
+	return config.Faint(strings.Join(items, "; "))
+}
 
+// the explanation of the breaking change, or "" if there isn't one
 func (m Model) Value() string {
+	if !m.breaking {
+		return ""
+	}
 	return m.input.Value()
 }
 
+// whether the commit is marked as a breaking change
+func (m Model) Breaking() bool {
+	return m.breaking
+}
+
+// answer the current question. Returns whether the step is done, which it
+// isn't after answering "yes" to the yes/no question.
+func (m Model) Confirm() (Model, bool) {
+	if m.breaking && !m.explaining {
+		m.explaining = true
+		m.input.Focus()
+		return m, false
+	}
+	return m, true
+}
+
+// show `err` under the explanation, asking for one if the commit is breaking
+func (m Model) SetErr(err error) Model {
+	m.input.Err = err
+	if m.breaking {
+		m.explaining = true
+		m.input.Focus()
+	}
+	return m
+}
+
+// whether `msg` answers the yes/no question "no", which records that the
+// commit isn't breaking and finishes the step without an explanation.
+func (m Model) Declines(msg tea.KeyMsg) bool {
+	return !m.explaining && (msg.String() == "n" || msg.String() == "N")
+}
+
 func (m Model) View() string {
-	return m.input.View() + "\n\n" + helpBar + "\n"
+	if m.explaining {
+		view := m.input.View()
+		if m.input.Err != nil {
+			view += "\n" + config.Underline(m.input.Err.Error())
+		}
+		return view + "\n\n" +
+			helpLine(config.HelpSubmit, config.HelpBack, config.HelpCancel) + "\n"
+	}
+	yes, no := "yes", "no"
+	if m.breaking {
+		yes = config.Underline(yes)
+	} else {
+		no = config.Underline(no)
+	}
+	return config.Faint(m.question) + yes + " / " + no +
+		"\n\n" +
+		helpLine(config.HelpToggle, config.HelpBreaking, config.HelpDecline, config.HelpSubmit, config.HelpBack, config.HelpCancel) + "\n"
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
+	if !m.explaining {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case msg.String() == "y" || msg.String() == "Y":
+				m.breaking = true
+			case msg.String() == "n" || msg.String() == "N":
+				m.breaking = false
+			case msg.Type == tea.KeyLeft || msg.Type == tea.KeyRight ||
+				msg.Type == tea.KeyUp || msg.Type == tea.KeyDown:
+				m.breaking = !m.breaking
+			}
+		}
+		return m, cmd
+	}
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.input.Err = nil
+	}
 	m.input, cmd = m.input.Update(msg)
 	return m, cmd
 }
 
-func NewModel() Model {
-	input := textinput.NewModel()
-	input.Prompt = termenv.String("Breaking changes: ").Faint().String()
-	input.Placeholder = "if any."
-	input.Focus()
+// the yes/no question starts answered `breaking`, and the explanation starts
+// as `explanation`, e.g. from an existing BREAKING CHANGE footer, or else as
+// the configured breaking_change_template.
+func NewModel(cfg config.Cfg, breaking bool, explanation string) Model {
+	input := config.NewTextInput()
+	input.Prompt = config.Faint(cfg.Prompt(config.PromptBreakingChangeWhy))
+	input.Placeholder = "optional; becomes the BREAKING CHANGE footer."
+	if explanation == "" {
+		explanation = cfg.BreakingChangeTemplate
+	}
+	input.SetValue(explanation)
+	input.SetCursor(len(explanation))
 	return Model{
-		input,
-		helpbar.NewModel(config.HelpSubmit, config.HelpBack, config.HelpCancel),
+		input:    input,
+		helpBar:  helpbar.NewModel(config.HelpToggle, config.HelpBreaking, config.HelpDecline, config.HelpSubmit, config.HelpBack, config.HelpCancel),
+		breaking: breaking,
+		question: cfg.Prompt(config.PromptBreakingChange),
 	}
 }
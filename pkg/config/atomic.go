@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// write `path` with `write` via a temporary file, so an interrupted write
+// leaves any existing file intact. `install` moves the finished temporary
+// file into place. An existing file keeps its mode rather than taking `perm`.
+func writeAtomic(
+	path string,
+	perm os.FileMode,
+	write func(io.Writer) error,
+	install func(tmp, path string) error,
+) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // a no-op once renamed into place
+	if err = write(tmp); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write to file %s: %w", path, err)
+	}
+	return install(tmp.Name(), path)
+}
+
+func writeData(data []byte) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+}
+
+// like os.WriteFile, but never leaves a partially-written file at `path`.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, perm, writeData(data), os.Rename)
+}
+
+// like WriteFileAtomic, but fails if `path` already exists.
+func createFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, perm, writeData(data), linkOrCreate(os.Link))
+}
+
+// install a temporary file with `link`, which fails if the target exists. On
+// filesystems without hard links, fall back to copying the file into an
+// exclusively-created target.
+func linkOrCreate(link func(oldname, newname string) error) func(tmp, path string) error {
+	return func(tmp, path string) error {
+		err := link(tmp, path)
+		if err == nil || errors.Is(err, os.ErrExist) {
+			return err
+		}
+		data, readErr := os.ReadFile(tmp)
+		info, statErr := os.Stat(tmp)
+		if readErr != nil || statErr != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(data); err == nil {
+			err = f.Sync()
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path) // don't leave a partial file behind
+			return fmt.Errorf("unable to write to file %s: %w", path, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "COMMIT_EDITMSG")
+	if err := WriteFileAtomic(path, []byte("feat: original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	interrupted := func(w io.Writer) error {
+		w.Write([]byte("fix: half"))
+		return errors.New("interrupted")
+	}
+	if err := writeAtomic(path, 0644, interrupted, os.Rename); err == nil {
+		t.Error("expected the interrupted write to fail")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "feat: original" {
+		t.Errorf("expected the original message to be intact, got %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected the temporary file to be cleaned up, got %d files", len(entries))
+	}
+}
+
+func TestCreateCfgFileExists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commit_convention.yml")
+	if err := CreateCfgFile(path, "scopes: []\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateCfgFile(path, "minimal: true\n"); err == nil {
+		t.Error("expected creating an existing config file to fail")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "scopes: []\n" {
+		t.Errorf("expected the existing config to be intact, got %q", data)
+	}
+}
+
+func TestWriteFileAtomicKeepsTheMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commit_convention.yml")
+	if err := os.WriteFile(path, []byte("scopes: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("minimal: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the existing mode to be kept, got %v, %v", info.Mode(), err)
+	}
+}
+
+func TestCreatingFilesWithoutHardLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commit_convention.yml")
+	unsupported := func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: errors.New("operation not supported")}
+	}
+	if err := writeAtomic(path, 0644, writeData([]byte("scopes: []\n")), linkOrCreate(unsupported)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeAtomic(path, 0644, writeData(nil), linkOrCreate(unsupported)); !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected creating an existing file to fail, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "scopes: []\n" {
+		t.Errorf("expected the created file to be intact, got %q", data)
+	}
+}
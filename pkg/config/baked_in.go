@@ -0,0 +1,68 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// a YAML config baked into the binary at build time, e.g. with
+//
+//	go build -ldflags "-X 'github.com/skalt/git-cc/pkg/config.BakedInConfig=header_max_length: 60'"
+//
+// If it's empty, baked_in_config.yml is used instead.
+var BakedInConfig string
+
+//go:embed baked_in_config.yml
+var embeddedConfig string
+
+// the baked-in defaults, which any config file, env var, or flag overrides
+var bakedIn = viper.New()
+
+func init() {
+	source := BakedInConfig
+	if source == "" {
+		source = embeddedConfig
+	}
+	if err := useBakedInConfig(source); err != nil {
+		panic(fmt.Errorf("invalid baked-in config: %v", err))
+	}
+}
+
+// check `source` against the Schema and use it as the defaults. Its
+// commit_types, if any, replace the AngularPresetCommitTypes, so
+// extend_default_types extends them instead.
+func useBakedInConfig(source string) error {
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(source), &document); err != nil {
+		return err
+	}
+	if document == nil { // only comments
+		bakedIn = viper.New()
+		return nil
+	}
+	if errs := ValidateAgainstSchema(document); len(errs) > 0 {
+		return errs[0]
+	}
+	store := viper.New()
+	store.SetConfigType("yaml")
+	if err := store.ReadConfig(bytes.NewBufferString(source)); err != nil {
+		return err
+	}
+	if store.IsSet("commit_types") {
+		types, err := flatOptions("commit_types", store.Get("commit_types"))
+		if err != nil {
+			return err
+		} else if types == nil {
+			if err = store.UnmarshalKey("commit_types", &types); err != nil {
+				return err
+			}
+		}
+		AngularPresetCommitTypes = types
+	}
+	bakedIn = store
+	return nil
+}
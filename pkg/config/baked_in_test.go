@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBakedInDefaults(t *testing.T) {
+	preset := AngularPresetCommitTypes
+	defer func() {
+		AngularPresetCommitTypes = preset
+		if err := useBakedInConfig(embeddedConfig); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	err := useBakedInConfig("commit_types: [feat, fix, sec]\nscopes: [api]\nheader_max_length: 60\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(contents string) {
+		path := filepath.Join(dir, "commit_convention.yml")
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("subject_case: any\n")
+	cfg := Lookup(InitFrom(dir))
+	expected := []map[string]string{{"feat": ""}, {"fix": ""}, {"sec": ""}}
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint(expected) {
+		t.Errorf("expected the baked-in types, got %v", cfg.CommitTypes)
+	}
+	if cfg.HeaderMaxLength != 60 || fmt.Sprint(cfg.Scopes) != "[map[api:]]" {
+		t.Errorf("expected the baked-in limit and scopes, got %d and %v", cfg.HeaderMaxLength, cfg.Scopes)
+	}
+	write("header_max_length: 50\ncommit_types: [wip]\nextend_default_types: true\n")
+	cfg = Lookup(InitFrom(dir))
+	if cfg.HeaderMaxLength != 50 {
+		t.Errorf("expected the config file to override the baked-in limit, got %d", cfg.HeaderMaxLength)
+	}
+	expected = append(expected, map[string]string{"wip": ""})
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint(expected) {
+		t.Errorf("expected the baked-in types to be extended, got %v", cfg.CommitTypes)
+	}
+	if err := useBakedInConfig("subject_case: title\n"); err == nil {
+		t.Error("expected an invalid baked-in config to be rejected")
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// matches branches like `feat/auth-login` or `fix/parser/empty-footers`
+const DefaultBranchPattern = `^(?P<type>[\w-]+)/(?:(?P<scope>[\w-]+)/)?`
+
+// the name of the checked-out branch, or "" if HEAD is detached or there's
+// no repository.
+func CurrentBranch() string {
+	out, err := Runner.Output("rev-parse", "--abbrev-ref", "HEAD")
+	branch := strings.TrimSpace(out)
+	if err != nil || branch == "HEAD" {
+		return ""
+	}
+	return branch
+}
+
+func compileBranchPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid branch_pattern %q: %w", pattern, err)
+	}
+	if re.SubexpIndex("type") < 0 && re.SubexpIndex("scope") < 0 {
+		return nil, fmt.Errorf(
+			"branch_pattern %q should capture a (?P<type>...) and/or (?P<scope>...) group",
+			pattern,
+		)
+	}
+	return re, nil
+}
+
+// the configured commit type and scope named by `branch`, if any. Types and
+// scopes that aren't configured are ignored.
+func (cfg Cfg) FromBranch(branch string) (commitType string, scope string) {
+	re, err := compileBranchPattern(cfg.BranchPattern)
+	if err != nil || branch == "" {
+		return "", ""
+	}
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		return "", ""
+	}
+	group := func(name string, options []map[string]string) string {
+		if i := re.SubexpIndex(name); i >= 0 {
+			for _, option := range options {
+				if _, ok := option[match[i]]; ok {
+					return match[i]
+				}
+			}
+		}
+		return ""
+	}
+	return group("type", cfg.CommitTypes), group("scope", cfg.Scopes)
+}
@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestReadingTheTypeFromTheBranch(t *testing.T) {
+	cfg := Cfg{
+		CommitTypes:   []map[string]string{{"feat": ""}, {"fix": ""}},
+		Scopes:        []map[string]string{{"parser": ""}},
+		BranchPattern: DefaultBranchPattern,
+	}
+	test := func(branch string, expectedType string, expectedScope string) func(*testing.T) {
+		return func(t *testing.T) {
+			commitType, scope := cfg.FromBranch(branch)
+			if commitType != expectedType || scope != expectedScope {
+				t.Errorf(
+					"expected (%q, %q), got (%q, %q)",
+					expectedType, expectedScope, commitType, scope,
+				)
+			}
+		}
+	}
+	t.Run("type", test("feat/auth-login", "feat", ""))
+	t.Run("type and scope", test("fix/parser/empty-footers", "fix", "parser"))
+	t.Run("unknown types", test("wip/auth-login", "", ""))
+	t.Run("unknown scopes", test("fix/cli/empty-footers", "fix", ""))
+	t.Run("non-matching branches", test("main", "", ""))
+	t.Run("detached HEAD", test("", "", ""))
+}
+
+func TestValidatingTheBranchPattern(t *testing.T) {
+	for _, pattern := range []string{`(`, `^feat/`} {
+		if _, err := compileBranchPattern(pattern); err == nil {
+			t.Errorf("expected %q to be rejected", pattern)
+		}
+	}
+}
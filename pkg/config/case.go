@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// valid values for the subject_case, scope_case, and type_case options
+const (
+	CaseAny      = "any"      // leave casing as typed
+	CaseLower    = "lower"    // lower-case the first letter
+	CaseSentence = "sentence" // upper-case the first letter
+)
+
+// the casing modes each option accepts
+var validCases = map[string][]string{
+	"subject_case": {CaseAny, CaseLower, CaseSentence},
+	"scope_case":   {CaseAny, CaseLower},
+	"type_case":    {CaseAny, CaseLower},
+}
+
+// returns an error if `value` isn't a valid casing mode for the option `key`.
+func ValidateCase(key string, value string) error {
+	for _, valid := range validCases[key] {
+		if value == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q; expected one of %v", key, value, validCases[key])
+}
+
+// change the casing of the first letter of `s` according to `mode`.
+func ApplyCase(mode string, s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	if first == utf8.RuneError {
+		return s
+	}
+	switch mode {
+	case CaseLower:
+		return string(unicode.ToLower(first)) + s[size:]
+	case CaseSentence:
+		return string(unicode.ToUpper(first)) + s[size:]
+	default:
+		return s
+	}
+}
+
+// whether `s` already complies with the casing `mode`.
+func MatchesCase(mode string, s string) bool {
+	return ApplyCase(mode, s) == s
+}
+
+// change the casing of a scope according to `mode`. Unlike ApplyCase, "lower"
+// lower-cases the entire scope.
+func ApplyScopeCase(mode string, scope string) string {
+	if mode == CaseLower {
+		return strings.ToLower(scope)
+	}
+	return scope
+}
+
+// `commitType`, lower-cased if normalize_type_case is on
+func (cfg Cfg) NormalizeType(commitType string) string {
+	if cfg.NormalizeTypeCase {
+		return strings.ToLower(commitType)
+	}
+	return commitType
+}
+
+// an error if type_case is "lower" and `commitType` isn't lower-case
+func (cfg Cfg) CheckTypeCase(commitType string) error {
+	if lower := strings.ToLower(commitType); cfg.TypeCase == CaseLower && lower != commitType {
+		return Error(ErrorTypeCase, commitType, lower)
+	}
+	return nil
+}
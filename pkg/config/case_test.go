@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestApplyingCase(t *testing.T) {
+	test := func(mode string, input string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			if actual := ApplyCase(mode, input); actual != expected {
+				t.Errorf("ApplyCase(%q, %q): expected %q, got %q", mode, input, expected, actual)
+			}
+			if MatchesCase(mode, input) != (input == expected) {
+				t.Errorf("MatchesCase(%q, %q) disagrees with ApplyCase", mode, input)
+			}
+		}
+	}
+	t.Run("sentence-cases ascii", test(CaseSentence, "add a feature", "Add a feature"))
+	t.Run("sentence-cases accented letters", test(CaseSentence, "über alles", "Über alles"))
+	t.Run("sentence-cases greek", test(CaseSentence, "ωmega", "Ωmega"))
+	t.Run("only changes the first letter", test(CaseSentence, "aDD", "ADD"))
+	t.Run("leaves uncased scripts alone", test(CaseSentence, "日本語", "日本語"))
+	t.Run("leaves empty input alone", test(CaseSentence, "", ""))
+	t.Run("lower-cases the first letter", test(CaseLower, "Émile", "émile"))
+	t.Run("any leaves input alone", test(CaseAny, "Add", "Add"))
+}
+
+func TestValidatingCase(t *testing.T) {
+	if err := ValidateCase("subject_case", CaseSentence); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateCase("subject_case", "title"); err == nil {
+		t.Error("expected an error for an unknown subject_case")
+	}
+}
+
+func TestApplyingScopeCase(t *testing.T) {
+	test := func(mode string, input string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			if actual := ApplyScopeCase(mode, input); actual != expected {
+				t.Errorf("ApplyScopeCase(%q, %q): expected %q, got %q", mode, input, expected, actual)
+			}
+		}
+	}
+	t.Run("lower-cases the entire scope", test(CaseLower, "AuthAPI", "authapi"))
+	t.Run("lower-cases non-ascii scopes", test(CaseLower, "Über", "über"))
+	t.Run("leaves lower-case scopes alone", test(CaseLower, "auth", "auth"))
+	t.Run("any leaves mixed case alone", test(CaseAny, "AuthAPI", "AuthAPI"))
+	if err := ValidateCase("scope_case", CaseSentence); err == nil {
+		t.Error("scope_case doesn't support sentence-casing")
+	}
+}
+
+func TestCheckingTypeCase(t *testing.T) {
+	cfg := Cfg{TypeCase: CaseLower}
+	for _, commitType := range []string{"Feat", "FIX", "feaT"} {
+		if err := cfg.CheckTypeCase(commitType); err == nil {
+			t.Errorf("expected %q to be rejected", commitType)
+		}
+	}
+	if err := cfg.CheckTypeCase("feat"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if actual := cfg.NormalizeType("Feat"); actual != "Feat" {
+		t.Errorf("expected types to be left alone without normalize_type_case, got %q", actual)
+	}
+	cfg.NormalizeTypeCase = true
+	if actual := cfg.NormalizeType("Feat"); actual != "feat" {
+		t.Errorf("expected 'feat', got %q", actual)
+	}
+	if err := (Cfg{TypeCase: CaseAny}).CheckTypeCase("Feat"); err != nil {
+		t.Errorf("expected type_case: any to allow mixed case, got %v", err)
+	}
+}
@@ -2,14 +2,20 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"unicode"
 
-	"github.com/muesli/termenv"
+	"github.com/mattn/go-runewidth"
+	"github.com/skalt/git-cc/pkg/parser"
 	"github.com/spf13/viper"
 )
 
@@ -42,56 +48,716 @@ var (
 	CentralStore *viper.Viper
 )
 
-const (
-	HelpSubmit = "submit: tab/enter"
-	HelpBack   = "go back: shift+tab"
-	HelpCancel = "cancel: ctrl+c"
-	HelpSelect = "navigate: up/down"
-)
-
-func Faint(s string) string {
-	return termenv.String(s).Faint().String()
-}
-
 type Cfg struct {
 	CommitTypes     []map[string]string `mapstructure:"commit_types"`
 	Scopes          []map[string]string `mapstructure:"scopes"`
 	HeaderMaxLength int                 `mapstructure:"header_max_length"`
 	//^ named similar to conventional-changelog/commitlint
 	EnforceMaxLength bool `mapstructure:"enforce_header_max_length"`
+	// what -m does with enforced, over-long headers: OnMaxLengthError or
+	// OnMaxLengthTruncate
+	OnMaxLength string `mapstructure:"on_max_length"`
+	// the casing of the first letter of the description; see ApplyCase
+	SubjectCase string `mapstructure:"subject_case"`
+	ScopeCase   string `mapstructure:"scope_case"` // see ApplyScopeCase
+	// "lower" to report upper-case letters in types; see CheckTypeCase
+	TypeCase string `mapstructure:"type_case"`
+	// whether to lower-case entered types rather than report them
+	NormalizeTypeCase bool `mapstructure:"normalize_type_case"`
+	// trailers appended to every commit, e.g. `Change-type: patch`
+	DefaultFooters []string `mapstructure:"default_footers"`
+	// per-type replacements for DefaultFooters
+	DefaultFootersByType map[string][]string `mapstructure:"default_footers_by_type"`
+	// where to write the scope: ScopePrefix or ScopeSuffix
+	ScopePosition string `mapstructure:"scope_position"`
+	// the most characters allowed in the body, excluding the header and
+	// footers; 0 means unlimited
+	BodyMaxLength int `mapstructure:"body_max_length"`
+	// FooterValuesTrim or FooterValuesVerbatim
+	FooterValues string `mapstructure:"footer_values"`
+	// whether to pre-select the type and scope named by the current branch
+	TypeFromBranch bool   `mapstructure:"type_from_branch"`
+	BranchPattern  string `mapstructure:"branch_pattern"` // see FromBranch
+	// whether to only prompt for the commit type and description
+	Minimal bool `mapstructure:"minimal"`
+	// whether CommitTypes add to, rather than replace, the Angular preset
+	ExtendDefaultTypes bool `mapstructure:"extend_default_types"`
+	// the order of the scope selector's options; see SortScopes
+	ScopeSort string `mapstructure:"scope_sort"`
+	// whether to append a summary of the staged changes to the body
+	IncludeDiffStat bool `mapstructure:"include_diff_stat"`
+	// the git binary to run, e.g. a wrapper or a pinned path
+	GitCommand string `mapstructure:"git_command"`
+	// whether to mark the header with a `!` when explaining a breaking change
+	BreakingChangeBang bool `mapstructure:"breaking_change_bang"`
+	// overrides of the DefaultPrompts
+	Prompts map[string]string `mapstructure:"prompts"`
+	// a shell command printing the scopes to use instead of Scopes; see
+	// CommandScopes
+	ScopesCommand string `mapstructure:"scopes_command"`
+	// the text to start each breaking-change explanation with
+	BreakingChangeTemplate string `mapstructure:"breaking_change_template"`
+	// commit type -> the trailer tokens each commit of that type must have
+	RequiredFooters map[string][]string `mapstructure:"required_footers"`
+	// a shell command rewriting each description; see FilterDescription
+	DescriptionFilter string `mapstructure:"description_filter"`
+	// a shell command rewriting each composed message; see FilterMessage
+	MessageFilter string `mapstructure:"message_filter"`
+	// whether to write the body and footers in the git editor after the TUI
+	BodyEditor bool `mapstructure:"body_editor"`
+	// whether to offer to stage all changes when nothing is staged
+	AutoStageWhenEmpty bool `mapstructure:"auto_stage_when_empty"`
+	// whether to number the first nine commit types for picking with 1-9
+	NumberedTypes bool `mapstructure:"numbered_types"`
+	// whether to ask for confirmation before committing a breaking change
+	ConfirmBreaking bool `mapstructure:"confirm_breaking"`
+	// whether to list the footers for removing or reordering before committing
+	ReviewFooters bool `mapstructure:"review_footers"`
+	// whether to detect scopes from the project layout when none are
+	// configured; see DetectScopes
+	ScopeAutodetect bool `mapstructure:"scope_autodetect"`
+	// the TUI's steps, in order; see ValidateSteps
+	Steps []string `mapstructure:"steps"`
+	// whether to mark the header_max_length column under the description
+	LengthRuler bool `mapstructure:"length_ruler"`
+	// a shell command to push with instead of `git push`, e.g. a wrapper
+	PushCommand string `mapstructure:"push_command"`
+	// where to read the active issue from to add as a `Refs:` footer: "env",
+	// "file", or "" for nowhere
+	IssueSource string `mapstructure:"issue_source"`
+	// the file holding the active issue, relative to the config file
+	IssueFile string `mapstructure:"issue_file"`
+	// the order to write footers in by token; "*" stands for unlisted tokens
+	FooterOrder []string `mapstructure:"footer_order"`
+	// whether breaking changes must be explained in a BREAKING CHANGE footer
+	RequireBreakingChangeDescription bool `mapstructure:"require_breaking_change_description"`
+	// commit type -> the scope to pre-select once that type is chosen
+	DefaultScopeByType map[string]string `mapstructure:"default_scope_by_type"`
+	// commit type -> the label the TUI shows for it, e.g. "✨ feature"
+	TypeLabels map[string]string `mapstructure:"type_labels"`
+	// commit types whose commits must have a scope
+	RequireScopeFor []string `mapstructure:"require_scope_for"`
+	// commit type -> the only scopes its commits may have
+	ScopesByType map[string][]string `mapstructure:"scopes_by_type"`
+	// commit type -> its changelog heading and position; see ChangelogTypes
+	ChangelogSections map[string]ChangelogSection `mapstructure:"changelog_sections"`
+	// whether to hide commit types that changelog_sections leaves out, rather
+	// than list them under the DefaultChangelogSection
+	ChangelogHideUnmapped bool `mapstructure:"changelog_hide_unmapped"`
+	// descriptions starting with these words are flagged; see Placeholder
+	PlaceholderDescriptions []string `mapstructure:"placeholder_descriptions"`
+	// whether to refuse, rather than warn about, placeholder descriptions
+	BlockPlaceholderDescriptions bool `mapstructure:"block_placeholder_descriptions"`
+	// whether to refuse, rather than warn about, commit_types or scopes that
+	// are listed more than once
+	BlockDuplicateOptions bool `mapstructure:"block_duplicate_options"`
+	// the most characters allowed in a scope; 0 means unlimited
+	ScopeMaxLength int `mapstructure:"scope_max_length"`
+	// MoodImperative to flag descriptions like "added x"; see MoodSuggestion
+	SubjectMood string `mapstructure:"subject_mood"`
+	// what follows the type, scope, and `!`; see ParserOption
+	HeaderSeparator string `mapstructure:"header_separator"`
+	// a line, e.g. "---", that separates the body from the footers of messages
+	// passed to git-cc; see parser.ParseWithFooterMarker
+	FooterMarker string `mapstructure:"footer_marker"`
+	// the active issue read from the IssueSource, if any
+	issue string
+	// descriptions of the duplicate options Lookup dropped
+	duplicates []string
+}
+
+// the git binary every git invocation runs; set from git_command by Lookup.
+var GitCommand = "git"
+
+// a command running the configured git binary with `args`
+func Git(args ...string) *exec.Cmd {
+	return exec.Command(GitCommand, args...)
+}
+
+// runs git commands: Output captures a query's stdout, while Run connects a
+// possibly interactive command, like `git commit`, to the given streams.
+type GitRunner interface {
+	Output(args ...string) (string, error)
+	Run(stdin io.Reader, stdout, stderr io.Writer, args ...string) error
+}
+
+// runs the configured git binary
+type execGitRunner struct{}
+
+func (execGitRunner) Output(args ...string) (string, error) {
+	return stdoutFrom(append([]string{GitCommand}, args...)...)
+}
+
+func (execGitRunner) Run(stdin io.Reader, stdout, stderr io.Writer, args ...string) error {
+	cmd := Git(args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	return cmd.Run()
+}
+
+// the GitRunner behind each git command git-cc runs; tests can replace it to
+// run without a repository.
+var Runner GitRunner = execGitRunner{}
+
+const (
+	ScopePrefix = "prefix" // `type(scope): description`, per the spec
+	ScopeSuffix = "suffix" // `type: description (scope)`, for legacy conventions
+)
+
+const (
+	OnMaxLengthError    = "error"    // refuse to commit
+	OnMaxLengthTruncate = "truncate" // cut the description to fit
+)
+
+const (
+	FooterValuesTrim     = "trim"     // trim extra whitespace from footer values
+	FooterValuesVerbatim = "verbatim" // keep footer values exactly as typed
+)
+
+// apply the footer_values setting to each of the footers
+func (cfg Cfg) NormalizeFooters(footers []string) []string {
+	if cfg.FooterValues == FooterValuesVerbatim {
+		return footers
+	}
+	result := make([]string, len(footers))
+	for i, footer := range footers {
+		result[i] = parser.TrimFooterValue(footer)
+	}
+	return result
+}
+
+// the default footer_order: breaking changes first, then other footers, then
+// the trailers tools usually append.
+var DefaultFooterOrder = []string{"BREAKING CHANGE", "*", "Refs", "Co-authored-by", "Signed-off-by"}
+
+// sort footers by their token's position in footer_order, where "*" stands
+// for any unlisted token. Tokens are compared case-insensitively and
+// footers with the same position keep their order.
+func (cfg Cfg) OrderFooters(footers []string) []string {
+	order := cfg.FooterOrder
+	if len(order) == 0 {
+		order = DefaultFooterOrder
+	}
+	ranks, rest := map[string]int{}, len(order)
+	for i, token := range order {
+		if token == "*" {
+			rest = i
+		} else if _, ok := ranks[strings.ToLower(token)]; !ok {
+			ranks[strings.ToLower(token)] = i
+		}
+	}
+	rank := func(footer string) int {
+		token := strings.ToLower(parser.FooterTokenOf(footer))
+		if parser.IsBreakingChangeFooter(footer) {
+			token = "breaking change"
+		}
+		if i, ok := ranks[token]; ok {
+			return i
+		}
+		return rest
+	}
+	result := append([]string{}, footers...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return rank(result[i]) < rank(result[j])
+	})
+	return result
+}
+
+// append the `extra` options after the `base` options. Options already in
+// `base` keep their position but take their description from `extra`.
+func MergeOptions(base []map[string]string, extra []map[string]string) []map[string]string {
+	result := []map[string]string{}
+	positions := map[string]int{}
+	for _, options := range [][]map[string]string{base, extra} {
+		for _, option := range options {
+			for name, description := range option {
+				if i, ok := positions[name]; ok {
+					result[i] = map[string]string{name: description}
+					continue
+				}
+				positions[name] = len(result)
+				result = append(result, map[string]string{name: description})
+			}
+		}
+	}
+	return result
+}
+
+// `options` without the names listed more than once, keeping the first
+// occurrence of each, and a description of each name that was repeated along
+// with its 1-based positions in `key`.
+func DedupeOptions(key string, options []map[string]string) ([]map[string]string, []string) {
+	result := []map[string]string{}
+	positions := map[string][]int{}
+	order := []string{}
+	for i, option := range options {
+		kept := map[string]string{}
+		for name, description := range option {
+			if _, seen := positions[name]; !seen {
+				order = append(order, name)
+				kept[name] = description
+			}
+			positions[name] = append(positions[name], i+1)
+		}
+		if len(kept) > 0 {
+			result = append(result, kept)
+		}
+	}
+	duplicates := []string{}
+	for _, name := range order {
+		if len(positions[name]) < 2 {
+			continue
+		}
+		listed := make([]string, len(positions[name]))
+		for i, position := range positions[name] {
+			listed[i] = fmt.Sprint(position)
+		}
+		duplicates = append(duplicates, fmt.Sprintf(
+			"%s: %q is listed at positions %s",
+			key, name, strings.Join(listed, ", "),
+		))
+	}
+	return result, duplicates
+}
+
+// describes each commit type or scope that Lookup found listed more than once
+func (cfg Cfg) DuplicateWarnings() []string {
+	return cfg.duplicates
+}
+
+// the option lists that may be written as a flat list of names
+var flatOptionKeys = []string{"commit_types", "scopes"}
+
+// read a flat list of option names, e.g. `[api, web]`, as {name: description}
+// options with empty descriptions. Returns nil if `value` isn't such a list,
+// and an error if it mixes names with `name: description` pairs.
+func flatOptions(key string, value interface{}) ([]map[string]string, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	result := []map[string]string{}
+	for _, item := range items {
+		if name, ok := item.(string); ok {
+			result = append(result, map[string]string{name: ""})
+		}
+	}
+	switch len(result) {
+	case 0:
+		return nil, nil
+	case len(items):
+		return result, nil
+	default:
+		return nil, fmt.Errorf(
+			"%s: list either names or `name: description` pairs, not both", key,
+		)
+	}
+}
+
+// the footers to append to commits of type `commitType`, including a `Refs:`
+// footer for the active issue unless another `Refs:` footer is configured.
+func (cfg Cfg) FootersFor(commitType string) []string {
+	footers, ok := cfg.DefaultFootersByType[commitType]
+	if !ok {
+		footers = cfg.DefaultFooters
+	}
+	if cfg.issue != "" {
+		footers = parser.MergeFooters(footers, []string{"Refs: " + cfg.issue})
+	}
+	return footers
+}
+
+// reported when require_breaking_change_description is set and a breaking
+// change isn't explained
+func BreakingChangeDescriptionError() error {
+	return Error(ErrorBreakingChangeDescription)
+}
+
+// whether a commit marked `breaking` lacks the non-empty BREAKING CHANGE
+// footer that require_breaking_change_description asks for.
+func (cfg Cfg) NeedsBreakingChangeDescription(breaking bool, footers []string) bool {
+	if !cfg.RequireBreakingChangeDescription || !breaking {
+		return false
+	}
+	for _, footer := range footers {
+		if !parser.IsBreakingChangeFooter(footer) {
+			continue
+		}
+		if description := strings.SplitN(footer, ":", 2)[1]; strings.TrimSpace(description) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	IssueSourceEnv  = "env"
+	IssueSourceFile = "file"
+	// the environment variable read by the "env" issue_source
+	IssueEnv = "GITCC_ISSUE"
+)
+
+// the active issue from `source`, or "" if there's none. Relative `file`s are
+// read from `dir`.
+func readIssue(source string, file string, dir string) string {
+	var issue string
+	switch source {
+	case IssueSourceEnv:
+		issue = os.Getenv(IssueEnv)
+	case IssueSourceFile:
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "" // no active issue
+		}
+		issue = strings.SplitN(string(data), "\n", 2)[0]
+	}
+	return strings.TrimSpace(issue)
+}
+
+// check that each of default_scope_by_type's types and scopes is configured.
+func (cfg Cfg) ValidateDefaultScopes() error {
+	has := func(options []map[string]string, name string) bool {
+		for _, option := range options {
+			if _, ok := option[name]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	types := make([]string, 0, len(cfg.DefaultScopeByType))
+	for commitType := range cfg.DefaultScopeByType {
+		types = append(types, commitType)
+	}
+	sort.Strings(types)
+	for _, commitType := range types {
+		scope := cfg.DefaultScopeByType[commitType]
+		if !has(cfg.CommitTypes, commitType) {
+			return fmt.Errorf("default_scope_by_type: unknown commit type %q", commitType)
+		}
+		if !has(cfg.Scopes, scope) {
+			return fmt.Errorf("default_scope_by_type: %q's default scope %q isn't one of the scopes", commitType, scope)
+		}
+	}
+	return nil
+}
+
+// check that each of scopes_by_type's types and scopes is configured, and that
+// default_scope_by_type's defaults are among the allowed scopes.
+func (cfg Cfg) ValidateScopesByType() error {
+	types := make([]string, 0, len(cfg.ScopesByType))
+	for commitType := range cfg.ScopesByType {
+		types = append(types, commitType)
+	}
+	sort.Strings(types)
+	for _, commitType := range types {
+		if !(&parser.CC{Type: commitType}).ValidCommitType(cfg.CommitTypes) {
+			return fmt.Errorf("scopes_by_type: unknown commit type %q", commitType)
+		}
+		for _, scope := range cfg.ScopesByType[commitType] {
+			if !(&parser.CC{Scope: scope}).ValidScope(cfg.Scopes) {
+				return fmt.Errorf("scopes_by_type: %q's scope %q isn't one of the scopes", commitType, scope)
+			}
+		}
+		if scope, ok := cfg.DefaultScopeByType[commitType]; ok && cfg.CheckScopeAllowed(commitType, scope) != nil {
+			return fmt.Errorf("default_scope_by_type: %q's default scope %q isn't one of its scopes_by_type", commitType, scope)
+		}
+	}
+	return nil
+}
+
+// the `scopes` that scopes_by_type allows `commitType`, or all of them if it
+// doesn't list the type
+func (cfg Cfg) AllowedScopes(commitType string, scopes []map[string]string) []map[string]string {
+	if _, ok := cfg.ScopesByType[commitType]; !ok {
+		return scopes
+	}
+	allowed := []map[string]string{}
+	for _, option := range scopes {
+		for scope := range option {
+			if cfg.CheckScopeAllowed(commitType, scope) == nil {
+				allowed = append(allowed, option)
+			}
+		}
+	}
+	return allowed
+}
+
+// an error if scopes_by_type lists `commitType` without `scope`. An empty scope
+// is left to require_scope_for.
+func (cfg Cfg) CheckScopeAllowed(commitType string, scope string) error {
+	allowed, ok := cfg.ScopesByType[commitType]
+	if scope == "" || !ok {
+		return nil
+	}
+	for _, name := range allowed {
+		if name == scope {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w; %s", Error(ErrorScopeNotAllowed, commitType, scope), Tf(HintAllowedScopes, strings.Join(allowed, ", ")))
+}
+
+// an error if `header` takes up more than header_max_length columns, which is
+// how the TUI measures it, too
+func (cfg Cfg) CheckHeaderLength(header string) error {
+	length := Width(header)
+	if length <= cfg.HeaderMaxLength {
+		return nil
+	}
+	return Error(ErrorHeaderLength, length, cfg.HeaderMaxLength)
+}
+
+// `description` cut so that `header`, which contains it, fits within
+// header_max_length, or "" if nothing of it would fit
+func (cfg Cfg) TruncateDescription(header string, description string) string {
+	room := Width(description) - (Width(header) - cfg.HeaderMaxLength)
+	if room <= 0 {
+		return ""
+	}
+	return strings.TrimRightFunc(runewidth.Truncate(description, room, ""), unicode.IsSpace)
+}
+
+// an error if `scope` is longer than scope_max_length
+func (cfg Cfg) CheckScopeLength(scope string) error {
+	length := len([]rune(scope))
+	if cfg.ScopeMaxLength == 0 || length <= cfg.ScopeMaxLength {
+		return nil
+	}
+	return Error(ErrorScopeLength, length, cfg.ScopeMaxLength)
+}
+
+// an error if `body` is longer than body_max_length
+func (cfg Cfg) CheckBodyLength(body string) error {
+	length := len([]rune(body))
+	if cfg.BodyMaxLength == 0 || length <= cfg.BodyMaxLength {
+		return nil
+	}
+	return Error(ErrorBodyLength, length, cfg.BodyMaxLength)
+}
+
+// an error if `commitType` is one of require_scope_for's types and `scope` is
+// empty
+func (cfg Cfg) CheckScopeRequired(commitType string, scope string) error {
+	if scope != "" {
+		return nil
+	}
+	for _, required := range cfg.RequireScopeFor {
+		if required == commitType {
+			return Error(ErrorScopeRequired, commitType)
+		}
+	}
+	return nil
+}
+
+// an error naming the `missing` footers that `commitType` commits need
+func MissingFootersError(commitType string, missing []string) error {
+	return Error(ErrorFooterRequired, commitType, strings.Join(missing, T(ErrorFooterRequiredJoin)))
+}
+
+// the required_footers tokens for `commitType` that none of `footers` have.
+// Tokens are compared case-insensitively, like git compares trailers.
+func (cfg Cfg) MissingFooters(commitType string, footers []string) []string {
+	missing := []string{}
+	for _, token := range cfg.RequiredFooters[commitType] {
+		found := false
+		for _, footer := range footers {
+			if strings.EqualFold(parser.FooterTokenOf(footer), token) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, token)
+		}
+	}
+	return missing
 }
 
 // viper: need to deserialize YAML commit-type options
 // viper: need to deserialize YAML scope options
 func Init() *viper.Viper {
-	CentralStore = viper.New()
-	CentralStore.SetConfigName("commit_convention")
-	CentralStore.SetConfigType("yaml")
-	cwd, _ := filepath.Abs(".")
-	// HACK: walk upwards in search of configuration rather than using the root
-	// of the git repo
-	paths := strings.Split(cwd, string(filepath.Separator))
-	for i := 0; i < len(paths); i++ {
-		path := string(os.PathSeparator) + filepath.Join(paths[0:len(paths)-i]...)
+	return InitFrom(".")
+}
+
+// like Init, but searches for the config file upwards from `dir` rather than
+// the working directory.
+func InitFrom(dir string) *viper.Viper {
+	store, _ := InitSearching(dir, nil, true) // no paths to check
+	return store
+}
+
+// like InitFrom, but searches the directories in `paths` first, in order. The
+// search only walks upwards from `dir` and then checks $HOME and
+// $XDG_CONFIG_HOME/git-cc if `walk` is true. Returns an error if any of the
+// `paths` isn't a directory.
+func InitSearching(dir string, paths []string, walk bool) (*viper.Viper, error) {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("invalid config path: %w", err)
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("invalid config path: %s is not a directory", path)
+		}
+	}
+	CentralStore = newStore()
+	for _, path := range paths {
 		CentralStore.AddConfigPath(path)
 	}
+	if walk {
+		start, _ := filepath.Abs(dir)
+		// HACK: walk upwards in search of configuration rather than using the
+		// root of the git repo
+		parents := strings.Split(start, string(filepath.Separator))
+		for i := 0; i < len(parents); i++ {
+			path := string(os.PathSeparator) + filepath.Join(parents[0:len(parents)-i]...)
+			CentralStore.AddConfigPath(path)
+		}
+		// viper expands an unset $HOME to "", which it then resolves to the
+		// working directory, so unset directories are left out
+		if os.Getenv("HOME") != "" {
+			CentralStore.AddConfigPath("$HOME")
+		}
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			CentralStore.AddConfigPath(filepath.Join(xdg, "git-cc"))
+		}
+	}
+	CentralStore.BindEnv("git_command", "GITCC_GIT")
+	CentralStore.BindEnv("enforce_header_max_length", "GITCC_ENFORCE_HEADER_MAX_LENGTH")
+	// the name of a profile in the config file's `profiles` map to merge over
+	// the base configuration.
+	CentralStore.BindEnv("profile", "GITCC_PROFILE")
+	return CentralStore, nil
+}
+
+// like Init, but without searching for a config file or reading environment
+// variables, so that only the defaults apply, including any BakedInConfig.
+func InitDefaults() *viper.Viper {
+	CentralStore = newStore()
+	CentralStore.Set(noConfigKey, true)
+	return CentralStore
+}
+
+// the header separator that the conventional commits spec requires
+const StandardHeaderSeparator = parser.StandardHeaderSeparator
 
-	CentralStore.AddConfigPath("$HOME")
+// the header_separator, or the standard one if it's unset
+func (cfg Cfg) Separator() string {
+	if cfg.HeaderSeparator == "" {
+		return StandardHeaderSeparator
+	}
+	return cfg.HeaderSeparator
+}
 
-	CentralStore.SetDefault("commit_types", AngularPresetCommitTypes)
-	CentralStore.SetDefault("scopes", map[string]string{})
-	CentralStore.SetDefault("header_max_length", 72)
-	CentralStore.SetDefault("enforce_header_max_length", false)
+// parses and formats messages with the header_separator
+func (cfg Cfg) ParserOption() parser.Option {
+	return parser.WithHeaderSeparator(cfg.HeaderSeparator)
+}
+
+// describes a non-standard header_separator, or returns "" for the standard
+// one or an unset one
+func (cfg Cfg) SeparatorWarning() string {
+	if cfg.HeaderSeparator == StandardHeaderSeparator || cfg.HeaderSeparator == "" {
+		return ""
+	}
+	return fmt.Sprintf(
+		"header_separator %q isn't %q, so other conventional commit tools won't parse these headers",
+		cfg.HeaderSeparator, StandardHeaderSeparator,
+	)
+}
+
+// marks stores from InitDefaults, which also ignore $GITCC_CONFIG_YAML
+const noConfigKey = "no_config"
+
+// a store holding the defaults, which searches no config paths yet
+func newStore() *viper.Viper {
+	store := viper.New()
+	store.SetConfigName("commit_convention")
+	store.SetConfigType("yaml")
+	store.SetDefault("commit_types", AngularPresetCommitTypes)
+	store.SetDefault("scopes", map[string]string{})
+	store.SetDefault("header_max_length", 72)
+	store.SetDefault("enforce_header_max_length", false)
+	store.SetDefault("on_max_length", OnMaxLengthError)
+	store.SetDefault("subject_case", CaseAny)
+	store.SetDefault("scope_case", CaseAny)
+	store.SetDefault("type_case", CaseAny)
+	store.SetDefault("normalize_type_case", false)
+	store.SetDefault("default_footers", []string{})
+	store.SetDefault("scope_position", ScopePrefix)
+	store.SetDefault("body_max_length", 0)
+	store.SetDefault("footer_values", FooterValuesTrim)
+	store.SetDefault("type_from_branch", false)
+	store.SetDefault("branch_pattern", DefaultBranchPattern)
+	store.SetDefault("minimal", false)
+	store.SetDefault("extend_default_types", false)
+	store.SetDefault("scope_sort", ScopeSortConfig)
+	store.SetDefault("include_diff_stat", false)
+	store.SetDefault("git_command", "git")
+	store.SetDefault("breaking_change_bang", true)
+	store.SetDefault("scopes_command", "")
+	store.SetDefault("breaking_change_template", "")
+	store.SetDefault("required_footers", map[string][]string{})
+	store.SetDefault("require_scope_for", []string{})
+	store.SetDefault("scopes_by_type", map[string][]string{})
+	store.SetDefault("changelog_sections", map[string]interface{}{})
+	store.SetDefault("changelog_hide_unmapped", false)
+	store.SetDefault("type_labels", map[string]string{})
+	store.SetDefault("description_filter", "")
+	store.SetDefault("message_filter", "")
+	store.SetDefault("body_editor", false)
+	store.SetDefault("auto_stage_when_empty", false)
+	store.SetDefault("numbered_types", false)
+	store.SetDefault("confirm_breaking", false)
+	store.SetDefault("review_footers", false)
+	store.SetDefault("scope_autodetect", false)
+	store.SetDefault("steps", DefaultSteps)
+	store.SetDefault("length_ruler", false)
+	store.SetDefault("push_command", "")
+	store.SetDefault("issue_source", "")
+	store.SetDefault("issue_file", ".current-issue")
+	store.SetDefault("footer_order", DefaultFooterOrder)
+	store.SetDefault("require_breaking_change_description", false)
+	store.SetDefault("default_scope_by_type", map[string]string{})
+	store.SetDefault("placeholder_descriptions", DefaultPlaceholderDescriptions)
+	store.SetDefault("block_placeholder_descriptions", false)
+	store.SetDefault("block_duplicate_options", false)
+	store.SetDefault("scope_max_length", 0)
+	store.SetDefault("subject_mood", MoodAny)
+	store.SetDefault("header_separator", StandardHeaderSeparator)
+	store.SetDefault("footer_marker", "")
+	for key, value := range bakedIn.AllSettings() {
+		store.SetDefault(key, value)
+	}
 	// s.t. `git log --oneline` should remain within 80 columns w/ a 7-rune
 	// commit hash and one space before the commit message.
 	// this caps the max len of the `type(scope): description`, not the body
 	// TODO: use env vars?
+	return store
+}
 
-	return CentralStore
+// the environment variable holding an inline YAML config, e.g. for CI
+// containers, which replaces the config file search.
+const ConfigYAMLEnv = "GITCC_CONFIG_YAML"
+
+// read the inline config from $GITCC_CONFIG_YAML if it's set, or else the
+// nearest config file.
+func readConfig(cfg *viper.Viper) error {
+	if cfg.GetBool(noConfigKey) {
+		return nil
+	}
+	inline := os.Getenv(ConfigYAMLEnv)
+	if inline == "" {
+		return cfg.ReadInConfig()
+	}
+	if err := cfg.ReadConfig(bytes.NewBufferString(inline)); err != nil {
+		return fmt.Errorf("invalid $%s: %w", ConfigYAMLEnv, err)
+	}
+	return nil
 }
 
 func Lookup(cfg *viper.Viper) Cfg {
-	err := cfg.ReadInConfig()
+	err := readConfig(cfg)
 	if err != nil {
 		switch err.(type) {
 		case viper.ConfigFileNotFoundError:
@@ -101,23 +767,210 @@ func Lookup(cfg *viper.Viper) Cfg {
 			log.Fatal(err)
 		}
 	}
+	if profile := cfg.GetString("profile"); profile != "" {
+		overrides := cfg.Sub("profiles." + profile)
+		if overrides == nil {
+			log.Fatalf("profile %q not found in %s", profile, cfg.ConfigFileUsed())
+		}
+		for key, value := range overrides.AllSettings() {
+			cfg.Set(key, value)
+		}
+	}
+	for _, key := range flatOptionKeys {
+		options, err := flatOptions(key, cfg.Get(key))
+		if err != nil {
+			log.Fatal(err)
+		} else if options != nil {
+			cfg.Set(key, options)
+		}
+	}
 	var data Cfg
 	err = cfg.Unmarshal(&data)
 	if err != nil {
 		log.Fatal(err)
 	}
+	var duplicates []string
+	data.CommitTypes, duplicates = DedupeOptions("commit_types", data.CommitTypes)
+	data.duplicates = append(data.duplicates, duplicates...)
+	data.Scopes, duplicates = DedupeOptions("scopes", data.Scopes)
+	data.duplicates = append(data.duplicates, duplicates...)
+	if data.BlockDuplicateOptions && len(data.duplicates) > 0 {
+		log.Fatalf("duplicate options:\n%s", strings.Join(data.duplicates, "\n"))
+	}
+	if data.ExtendDefaultTypes {
+		data.CommitTypes = MergeOptions(AngularPresetCommitTypes, data.CommitTypes)
+	}
+	if err = ValidateCase("subject_case", data.SubjectCase); err != nil {
+		log.Fatal(err)
+	}
+	if err = ValidateCase("scope_case", data.ScopeCase); err != nil {
+		log.Fatal(err)
+	}
+	if err = ValidateCase("type_case", data.TypeCase); err != nil {
+		log.Fatal(err)
+	}
+	for _, option := range data.CommitTypes {
+		for commitType := range option {
+			if err = data.CheckTypeCase(commitType); err != nil {
+				log.Fatalf("invalid commit_types: %v", err)
+			}
+		}
+	}
+	if data.ScopePosition != ScopePrefix && data.ScopePosition != ScopeSuffix {
+		log.Fatalf(
+			"invalid scope_position %q; expected %q or %q",
+			data.ScopePosition, ScopePrefix, ScopeSuffix,
+		)
+	}
+	if data.OnMaxLength != OnMaxLengthError && data.OnMaxLength != OnMaxLengthTruncate {
+		log.Fatalf(
+			"invalid on_max_length %q; expected %q or %q",
+			data.OnMaxLength, OnMaxLengthError, OnMaxLengthTruncate,
+		)
+	}
+	if data.FooterValues != FooterValuesTrim && data.FooterValues != FooterValuesVerbatim {
+		log.Fatalf(
+			"invalid footer_values %q; expected %q or %q",
+			data.FooterValues, FooterValuesTrim, FooterValuesVerbatim,
+		)
+	}
+	if data.SubjectMood != MoodAny && data.SubjectMood != MoodImperative {
+		log.Fatalf(
+			"invalid subject_mood %q; expected %q or %q",
+			data.SubjectMood, MoodAny, MoodImperative,
+		)
+	}
+	if data.HeaderSeparator == "" || strings.ContainsAny(data.HeaderSeparator, "\r\n") {
+		log.Fatalf("invalid header_separator %q; expected a non-empty, single-line string", data.HeaderSeparator)
+	}
+	if data.FooterMarker != strings.TrimSpace(data.FooterMarker) || strings.ContainsAny(data.FooterMarker, "\r\n") {
+		log.Fatalf("invalid footer_marker %q; expected a single line without surrounding spaces", data.FooterMarker)
+	}
+	if data.GitCommand != "" {
+		GitCommand = data.GitCommand
+	}
+	switch data.ScopeSort {
+	case ScopeSortConfig, ScopeSortAlpha, ScopeSortRecency:
+	default:
+		log.Fatalf(
+			"invalid scope_sort %q; expected %q, %q, or %q",
+			data.ScopeSort, ScopeSortConfig, ScopeSortAlpha, ScopeSortRecency,
+		)
+	}
+	switch data.IssueSource {
+	case "", IssueSourceEnv, IssueSourceFile:
+	default:
+		log.Fatalf(
+			"invalid issue_source %q; expected %q, %q, or \"\"",
+			data.IssueSource, IssueSourceEnv, IssueSourceFile,
+		)
+	}
+	dir := "."
+	if used := cfg.ConfigFileUsed(); used != "" {
+		dir = filepath.Dir(used)
+	}
+	data.issue = readIssue(data.IssueSource, data.IssueFile, dir)
+	if err = ValidatePrompts(data.Prompts); err != nil {
+		log.Fatal(err)
+	}
+	if err = ValidateSteps(data.Steps); err != nil {
+		log.Fatal(err)
+	}
+	if _, err = compileBranchPattern(data.BranchPattern); err != nil {
+		log.Fatal(err)
+	}
+	if data.ScopesCommand != "" {
+		if scopes, err := CommandScopes(data.ScopesCommand); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: scopes_command failed, using scopes instead: %v\n", err)
+		} else {
+			data.Scopes = scopes
+		}
+	}
+	if len(data.Scopes) == 0 && data.ScopeAutodetect {
+		root, err := Runner.Output("rev-parse", "--show-toplevel")
+		if err != nil {
+			root = "."
+		}
+		data.Scopes = DetectScopes(strings.TrimSpace(root))
+	}
+	if err = data.ValidateDefaultScopes(); err != nil {
+		log.Fatal(err)
+	}
+	for commitType, label := range data.TypeLabels {
+		if !(&parser.CC{Type: commitType}).ValidCommitType(data.CommitTypes) {
+			log.Fatalf("type_labels: unknown commit type %q", commitType)
+		}
+		if strings.TrimSpace(label) == "" || strings.ContainsAny(label, "\r\n") {
+			log.Fatalf("type_labels: %q's label must be a non-empty single line", commitType)
+		}
+	}
+	for _, commitType := range data.RequireScopeFor {
+		if !(&parser.CC{Type: commitType}).ValidCommitType(data.CommitTypes) {
+			log.Fatalf("require_scope_for: unknown commit type %q", commitType)
+		}
+	}
+	if err = data.ValidateScopesByType(); err != nil {
+		log.Fatal(err)
+	}
+	if err = data.ValidateChangelogSections(); err != nil {
+		log.Fatal(err)
+	}
+	footers := append([]string{}, data.DefaultFooters...)
+	for _, byType := range data.DefaultFootersByType {
+		footers = append(footers, byType...)
+	}
+	for _, footer := range footers {
+		if parser.FooterTokenOf(footer) == "" {
+			log.Fatalf("default footer %q should look like `Token: value`", footer)
+		}
+	}
 	return data
 }
 func stdoutFrom(args ...string) (string, error) {
 	cmd := exec.Command(args[0], args[1:]...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
+	var out, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &stderr
 	err := cmd.Run()
+	if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+		err = fmt.Errorf("%w: %s", err, msg)
+	}
 	return out.String(), err
 }
 
+// whether git's commit.verbose config adds the staged diff to commit messages
+func VerboseCommits() bool {
+	out, err := Runner.Output("config", "--bool", "--get", "commit.verbose")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// the line ending git's core.eol asks for: "\r\n" for crlf, "\n" for lf, and
+// the platform's for native or if it's unset.
+func LineEnding() string {
+	out, _ := Runner.Output("config", "--get", "core.eol")
+	switch strings.TrimSpace(out) {
+	case "crlf":
+		return "\r\n"
+	case "lf":
+		return "\n"
+	}
+	if runtime.GOOS == "windows" {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// `text` with every line ending, whether "\r\n", "\n", or a lone "\r",
+// replaced by `eol`
+func NormalizeLineEndings(text string, eol string) string {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+	if eol == "\n" {
+		return text
+	}
+	return strings.ReplaceAll(text, "\n", eol)
+}
+
 func getGitVar(var_name string) (string, error) {
-	out, err := stdoutFrom("git", "var", var_name)
+	out, err := Runner.Output("var", var_name)
 	if err != nil {
 		return "", err
 	} else {
@@ -142,41 +995,95 @@ func GetGitEditor() string {
 	return editor
 }
 
+// the absolute path to the current repository's .git directory
+func gitDir() (string, error) {
+	out, err := Runner.Output("rev-parse", "--absolute-git-dir")
+	return strings.TrimRight(out, " \t\r\n"), err
+}
+
+// the path of the file git-cc writes commit messages to, or the error from
+// resolving the git directory.
+func CommitMessageFile() (string, error) {
+	dir, err := gitDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the git directory: %w", err)
+	}
+	return strings.Join([]string{dir, "COMMIT_EDITMSG"}, string(os.PathSeparator)), nil
+}
+
+// the path of the draft git-cc keeps of a message git failed to commit, or
+// the error from resolving the git directory.
+func DraftFile() (string, error) {
+	dir, err := gitDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the git directory: %w", err)
+	}
+	return filepath.Join(dir, "GITCC_DRAFT"), nil
+}
+
 func GetCommitMessageFile() string {
-	out, err := stdoutFrom("git", "rev-parse", "--absolute-git-dir")
+	file, err := CommitMessageFile()
 	if err != nil {
 		log.Fatal(err)
 	}
-	return strings.Join(
-		[]string{strings.TrimRight(out, " \t\r\n"), "COMMIT_EDITMSG"},
-		string(os.PathSeparator),
-	)
+	return file
+}
+
+// the full message of the commit `rev`
+func CommitMessage(rev string) (string, error) {
+	out, err := Runner.Output("log", "-1", "--format=%B", rev, "--")
+	if err != nil {
+		return "", fmt.Errorf("unable to read the message of %q: %w", rev, err)
+	}
+	return out, nil
+}
+
+// whether a merge is waiting to be committed, in which case git has already
+// drafted the commit message in MERGE_MSG.
+func MergeInProgress() bool {
+	dir, err := gitDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, "MERGE_MSG"))
+	return err == nil
+}
+
+// returned by EditCfgFile when there's no config file to edit
+var ErrNoCfgFile = errors.New(
+	"no commit_convention.yml found; create one with `git cc config init`",
+)
+
+// write `content` to a new config file at `path`, failing if it already exists.
+func CreateCfgFile(path string, content string) error {
+	if err := createFileAtomic(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("unable to create file %s: %w", path, err)
+	}
+	return nil
+}
+
+// interactively edit the config file in use, returning ErrNoCfgFile rather
+// than creating one if there isn't any.
+func EditCfgFile(cfg *viper.Viper) (Cfg, error) {
+	cfgFile := cfg.ConfigFileUsed()
+	if cfgFile == "" {
+		return Cfg{}, ErrNoCfgFile
+	}
+	EditFile(GetEditor(), cfgFile) // ignore errors
+	return Lookup(cfg), nil
 }
 
-// interactively edit the config file, if any was used.
-func EditCfgFile(cfg *viper.Viper, defaultFileContent string) Cfg {
+// interactively edit `path` with `editor`, which may include arguments
+func EditFile(editor string, path string) error {
 	editCmd := []string{}
 	// sometimes $EDITOR can be a script with spaces, like `code --wait`
-	for _, part := range strings.Split(GetEditor(), " ") {
+	for _, part := range strings.Split(editor, " ") {
 		if part != "" {
 			editCmd = append(editCmd, part)
 		}
 	}
-	cfgFile := cfg.ConfigFileUsed()
-	if cfgFile == "" {
-		cfgFile = "commit_convention.yml" // TODO: verify that this is the correct location (i.e. the cwd or a parent directory)?
-		f, err := os.Create(cfgFile)
-		if err != nil {
-			log.Fatalf("unable to create file %s: %+v", cfgFile, err)
-		}
-		_, err = f.WriteString(fmt.Sprintf(defaultFileContent))
-		if err != nil {
-			log.Fatalf("unable to write to file: %v", err)
-		}
-	}
-	editCmd = append(editCmd, cfgFile)
+	editCmd = append(editCmd, path)
 	cmd := exec.Command(editCmd[0], editCmd[1:]...)
-	cmd.Stdin, cmd.Stdout = os.Stdin, os.Stderr
-	cmd.Run() // ignore errors
-	return Lookup(cfg)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+	return cmd.Run()
 }
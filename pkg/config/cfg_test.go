@@ -0,0 +1,517 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+func TestMergingOptions(t *testing.T) {
+	base := []map[string]string{{"feat": "adds a feature"}, {"fix": "fixes a bug"}}
+	extra := []map[string]string{{"wip": "work in progress"}, {"fix": "fixes a regression"}}
+	expected := []map[string]string{
+		{"feat": "adds a feature"},
+		{"fix": "fixes a regression"},
+		{"wip": "work in progress"},
+	}
+	if actual := MergeOptions(base, extra); fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if len(AngularPresetCommitTypes) != 11 {
+		t.Fatalf("unexpected preset")
+	}
+	merged := MergeOptions(AngularPresetCommitTypes, extra)
+	if len(merged) != 12 {
+		t.Errorf("expected the 11 preset types plus wip, got %v", merged)
+	}
+}
+
+func TestUsingTheConfiguredGit(t *testing.T) {
+	fake := filepath.Join(t.TempDir(), "fake-git")
+	script := "#!/bin/sh\necho \"fake/$*\"\n"
+	if err := os.WriteFile(fake, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(original string) { GitCommand = original }(GitCommand)
+	GitCommand = fake
+	if branch := CurrentBranch(); branch != "fake/rev-parse --abbrev-ref HEAD" {
+		t.Errorf("expected the fake git to run, got %q", branch)
+	}
+}
+
+// answers git commands from a fixed map of `args` to stdout
+type fakeGitRunner map[string]string
+
+func (f fakeGitRunner) Output(args ...string) (string, error) {
+	out, ok := f[strings.Join(args, " ")]
+	if !ok {
+		return "", fmt.Errorf("unexpected git %v", args)
+	}
+	return out, nil
+}
+
+func (f fakeGitRunner) Run(stdin io.Reader, stdout, stderr io.Writer, args ...string) error {
+	out, err := f.Output(args...)
+	fmt.Fprint(stdout, out)
+	return err
+}
+
+func TestQueryingGitThroughTheRunner(t *testing.T) {
+	dir := t.TempDir()
+	defer func(original GitRunner) { Runner = original }(Runner)
+	Runner = fakeGitRunner{
+		"rev-parse --absolute-git-dir": dir + "\n",
+		"rev-parse --abbrev-ref HEAD":  "HEAD\n",
+	}
+	if file := GetCommitMessageFile(); file != filepath.Join(dir, "COMMIT_EDITMSG") {
+		t.Errorf("unexpected message file %q", file)
+	}
+	Runner = fakeGitRunner{}
+	if _, err := CommitMessageFile(); err == nil {
+		t.Error("expected an error outside of a git repository")
+	}
+	Runner = fakeGitRunner{
+		"rev-parse --absolute-git-dir": dir + "\n",
+		"rev-parse --abbrev-ref HEAD":  "HEAD\n",
+	}
+	if MergeInProgress() {
+		t.Error("expected no merge in progress")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "MERGE_MSG"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !MergeInProgress() {
+		t.Error("expected a merge in progress")
+	}
+	if branch := CurrentBranch(); branch != "" {
+		t.Errorf("a detached HEAD shouldn't have a branch, got %q", branch)
+	}
+}
+
+func TestEditingWithoutACfgFile(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	store := viper.New()
+	if _, err := EditCfgFile(store); err != ErrNoCfgFile {
+		t.Errorf("expected ErrNoCfgFile, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("expected no files to be created, found %v", entries)
+	}
+	path := filepath.Join(dir, "commit_convention.yml")
+	if err := CreateCfgFile(path, ExampleCfgFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateCfgFile(path, ExampleCfgFile); err == nil {
+		t.Error("expected creating an existing file to fail")
+	}
+}
+
+func TestInitFromANestedPackage(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "packages", "api")
+	if err := os.MkdirAll(filepath.Join(pkg, "cmd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "scopes:\n  - handlers: the request handlers\n"
+	if err := os.WriteFile(filepath.Join(pkg, "commit_convention.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Lookup(InitFrom(filepath.Join(pkg, "cmd")))
+	if len(cfg.Scopes) != 1 || cfg.Scopes[0]["handlers"] == "" {
+		t.Errorf("expected the package's scopes, got %v", cfg.Scopes)
+	}
+}
+
+func TestFlatOptionLists(t *testing.T) {
+	dir := t.TempDir()
+	write := func(contents string) {
+		path := filepath.Join(dir, "commit_convention.yml")
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("scopes: [api, web]\n")
+	cfg := Lookup(InitFrom(dir))
+	expected := []map[string]string{{"api": ""}, {"web": ""}}
+	if fmt.Sprint(cfg.Scopes) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, cfg.Scopes)
+	}
+	write("scopes:\n  - api: the server\n  - web: the site\n")
+	cfg = Lookup(InitFrom(dir))
+	expected = []map[string]string{{"api": "the server"}, {"web": "the site"}}
+	if fmt.Sprint(cfg.Scopes) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, cfg.Scopes)
+	}
+	mixed := []interface{}{"api", map[string]interface{}{"web": "the site"}}
+	if _, err := flatOptions("scopes", mixed); err == nil {
+		t.Error("expected an error for a mix of names and descriptions")
+	}
+	if errs := ValidateAgainstSchema(map[string]interface{}{"scopes": []interface{}{"api", "web"}}); len(errs) > 0 {
+		t.Errorf("expected flat scopes to match the schema, got %v", errs)
+	}
+}
+
+func TestFlatCommitTypes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(contents string) {
+		path := filepath.Join(dir, "commit_convention.yml")
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("commit_types: [feat, fix, custom]\n")
+	cfg := Lookup(InitFrom(dir))
+	expected := []map[string]string{{"feat": ""}, {"fix": ""}, {"custom": ""}}
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, cfg.CommitTypes)
+	}
+	write("commit_types:\n  - feat: adds a feature\n  - custom: something else\n")
+	cfg = Lookup(InitFrom(dir))
+	expected = []map[string]string{{"feat": "adds a feature"}, {"custom": "something else"}}
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, cfg.CommitTypes)
+	}
+	mixed := []interface{}{map[string]interface{}{"feat": "adds a feature"}, "fix"}
+	_, err := flatOptions("commit_types", mixed)
+	if err == nil || !strings.Contains(err.Error(), "commit_types") {
+		t.Errorf("expected an error naming commit_types, got %v", err)
+	}
+	if errs := ValidateAgainstSchema(map[string]interface{}{"commit_types": []interface{}{"feat", 1}}); len(errs) == 0 {
+		t.Error("expected a number to be an invalid commit type")
+	}
+}
+
+func TestDuplicateOptions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(contents string) {
+		path := filepath.Join(dir, "commit_convention.yml")
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("commit_types:\n  - feat: adds a feature\n  - fix: fixes a bug\n  - feat: something else\nscopes: [api, web, api, api]\n")
+	cfg := Lookup(InitFrom(dir))
+	expectedTypes := []map[string]string{{"feat": "adds a feature"}, {"fix": "fixes a bug"}}
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint(expectedTypes) {
+		t.Errorf("expected %v, got %v", expectedTypes, cfg.CommitTypes)
+	}
+	expectedScopes := []map[string]string{{"api": ""}, {"web": ""}}
+	if fmt.Sprint(cfg.Scopes) != fmt.Sprint(expectedScopes) {
+		t.Errorf("expected %v, got %v", expectedScopes, cfg.Scopes)
+	}
+	expectedWarnings := []string{
+		`commit_types: "feat" is listed at positions 1, 3`,
+		`scopes: "api" is listed at positions 1, 3, 4`,
+	}
+	if fmt.Sprint(cfg.DuplicateWarnings()) != fmt.Sprint(expectedWarnings) {
+		t.Errorf("expected %q, got %q", expectedWarnings, cfg.DuplicateWarnings())
+	}
+	write("commit_types: [feat, fix]\nscopes: [api, web]\n")
+	if warnings := Lookup(InitFrom(dir)).DuplicateWarnings(); len(warnings) > 0 {
+		t.Errorf("expected no warnings, got %q", warnings)
+	}
+	write("commit_types: [feat, fix]\nextend_default_types: true\n")
+	if warnings := Lookup(InitFrom(dir)).DuplicateWarnings(); len(warnings) > 0 {
+		t.Errorf("expected overriding the preset not to warn, got %q", warnings)
+	}
+}
+
+func TestInlineConfigFromTheEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	contents := "commit_types: [feat]\nscopes: [from-file]\n"
+	if err := os.WriteFile(filepath.Join(dir, "commit_convention.yml"), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigYAMLEnv, "commit_types:\n  - ci: changes the pipeline\nscopes: [api, web]\n")
+	cfg := Lookup(InitFrom(dir))
+	if fmt.Sprint(cfg.CommitTypes) != fmt.Sprint([]map[string]string{{"ci": "changes the pipeline"}}) {
+		t.Errorf("expected the inline types, got %v", cfg.CommitTypes)
+	}
+	if fmt.Sprint(cfg.Scopes) != fmt.Sprint([]map[string]string{{"api": ""}, {"web": ""}}) {
+		t.Errorf("expected the inline scopes, got %v", cfg.Scopes)
+	}
+	if cfg.HeaderMaxLength != 72 {
+		t.Errorf("expected defaults under the inline config, got %d", cfg.HeaderMaxLength)
+	}
+	t.Setenv(ConfigYAMLEnv, "scopes: [api\n")
+	if err := readConfig(InitFrom(dir)); err == nil || !strings.Contains(err.Error(), ConfigYAMLEnv) {
+		t.Errorf("expected an error naming %s, got %v", ConfigYAMLEnv, err)
+	}
+}
+
+func TestAddingTheActiveIssue(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, contents string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(IssueEnv, "PROJ-12")
+	write("commit_convention.yml", "default_footers: [\"Change-type: patch\"]\n")
+	if footers := Lookup(InitFrom(dir)).FootersFor("feat"); fmt.Sprint(footers) != "[Change-type: patch]" {
+		t.Errorf("expected no issue footer by default, got %v", footers)
+	}
+	write("commit_convention.yml", "issue_source: env\n")
+	cfg := Lookup(InitFrom(dir))
+	if footers := cfg.FootersFor("feat"); fmt.Sprint(footers) != "[Refs: PROJ-12]" {
+		t.Errorf("expected the issue from the environment, got %v", footers)
+	}
+	if merged := parser.MergeFooters([]string{"Refs: #9"}, cfg.FootersFor("feat")); fmt.Sprint(merged) != "[Refs: #9]" {
+		t.Errorf("expected an existing Refs footer to win, got %v", merged)
+	}
+	write("commit_convention.yml", "issue_source: file\n")
+	if footers := Lookup(InitFrom(dir)).FootersFor("feat"); len(footers) != 0 {
+		t.Errorf("expected no footer without an issue file, got %v", footers)
+	}
+	write(".current-issue", "#42\nnotes\n")
+	if footers := Lookup(InitFrom(dir)).FootersFor("feat"); fmt.Sprint(footers) != "[Refs: #42]" {
+		t.Errorf("expected the issue from the file, got %v", footers)
+	}
+}
+
+func TestOrderingFooters(t *testing.T) {
+	footers := []string{
+		"Signed-off-by: A <a@example.com>",
+		"Refs: #1",
+		"Co-authored-by: B <b@example.com>",
+		"Reviewed-by: C",
+		"BREAKING CHANGE: drops x",
+		"Acked-by: D",
+	}
+	expected := []string{
+		"BREAKING CHANGE: drops x",
+		"Reviewed-by: C",
+		"Acked-by: D",
+		"Refs: #1",
+		"Co-authored-by: B <b@example.com>",
+		"Signed-off-by: A <a@example.com>",
+	}
+	ordered := Cfg{}.OrderFooters(footers)
+	if fmt.Sprint(ordered) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, ordered)
+	}
+	message := "feat!: x\n\n" + strings.Join(ordered, "\n") + "\n"
+	cc, err := parser.Parse(message)
+	if err != nil || fmt.Sprint(cc.Footers) != fmt.Sprint(expected) {
+		t.Errorf("expected the ordered footers to parse back, got %q, %v", cc.Footers, err)
+	}
+	if reordered := (Cfg{}).OrderFooters(cc.Footers); fmt.Sprint(reordered) != fmt.Sprint(expected) {
+		t.Errorf("expected ordering to be stable, got %q", reordered)
+	}
+	custom := Cfg{FooterOrder: []string{"refs", "*"}}.OrderFooters(footers)
+	if custom[0] != "Refs: #1" || custom[1] != footers[0] {
+		t.Errorf("expected Refs first and the rest in order, got %q", custom)
+	}
+}
+
+func TestValidatingDefaultScopes(t *testing.T) {
+	cfg := Cfg{
+		CommitTypes: []map[string]string{{"build": "changes the build"}},
+		Scopes:      []map[string]string{{"deps": "dependencies"}},
+	}
+	cases := []struct {
+		defaults map[string]string
+		valid    bool
+	}{
+		{map[string]string{}, true},
+		{map[string]string{"build": "deps"}, true},
+		{map[string]string{"build": "ci"}, false},
+		{map[string]string{"feat": "deps"}, false},
+	}
+	for _, c := range cases {
+		cfg.DefaultScopeByType = c.defaults
+		if err := cfg.ValidateDefaultScopes(); (err == nil) != c.valid {
+			t.Errorf("%v: expected valid=%v, got %v", c.defaults, c.valid, err)
+		}
+	}
+}
+
+func TestValidatingScopesByType(t *testing.T) {
+	cfg := Cfg{
+		CommitTypes:        []map[string]string{{"build": "changes the build"}, {"feat": "adds a feature"}},
+		Scopes:             []map[string]string{{"deps": "dependencies"}, {"ci": "the pipeline"}, {"cli": "the cli"}},
+		DefaultScopeByType: map[string]string{"build": "deps"},
+	}
+	cases := []struct {
+		byType map[string][]string
+		valid  bool
+	}{
+		{map[string][]string{}, true},
+		{map[string][]string{"build": {"deps", "ci"}}, true},
+		{map[string][]string{"build": {"ci"}}, false}, // excludes the default
+		{map[string][]string{"build": {"deps", "docs"}}, false},
+		{map[string][]string{"fix": {"cli"}}, false},
+	}
+	for _, c := range cases {
+		cfg.ScopesByType = c.byType
+		if err := cfg.ValidateScopesByType(); (err == nil) != c.valid {
+			t.Errorf("%v: expected valid=%v, got %v", c.byType, c.valid, err)
+		}
+	}
+}
+
+func TestAllowingScopesByType(t *testing.T) {
+	scopes := []map[string]string{{"deps": "dependencies"}, {"ci": "the pipeline"}, {"cli": "the cli"}}
+	cfg := Cfg{Scopes: scopes, ScopesByType: map[string][]string{"build": {"ci", "deps"}}}
+	allowed := cfg.AllowedScopes("build", scopes)
+	if len(allowed) != 2 || allowed[0]["deps"] == "" || allowed[1]["ci"] == "" {
+		t.Errorf("expected build's scopes in the given order, got %v", allowed)
+	}
+	if allowed := cfg.AllowedScopes("feat", scopes); len(allowed) != len(scopes) {
+		t.Errorf("expected unlisted types to allow every scope, got %v", allowed)
+	}
+	for _, c := range []struct {
+		commitType, scope string
+		valid             bool
+	}{
+		{"build", "deps", true},
+		{"build", "", true},
+		{"build", "cli", false},
+		{"feat", "cli", true},
+	} {
+		if err := cfg.CheckScopeAllowed(c.commitType, c.scope); (err == nil) != c.valid {
+			t.Errorf("%s(%s): expected valid=%v, got %v", c.commitType, c.scope, c.valid, err)
+		}
+	}
+}
+
+func TestCheckingScopeLength(t *testing.T) {
+	cfg := Cfg{}
+	if err := cfg.CheckScopeLength("a-very-long-scope-name"); err != nil {
+		t.Errorf("expected no limit by default, got %v", err)
+	}
+	cfg.ScopeMaxLength = 5
+	for scope, valid := range map[string]bool{"": true, "pars": true, "parse": true, "parser": false, "éééééé": false} {
+		if err := cfg.CheckScopeLength(scope); (err == nil) != valid {
+			t.Errorf("%q: expected valid=%v, got %v", scope, valid, err)
+		}
+	}
+}
+
+func TestSearchingExplicitConfigPaths(t *testing.T) {
+	root := t.TempDir()
+	write := func(dir string, scope string) string {
+		path := filepath.Join(root, dir)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		config := "scopes:\n  - " + scope + ": a scope\n"
+		if err := os.WriteFile(filepath.Join(path, "commit_convention.yml"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	first, second, work := write("first", "api"), write("second", "web"), write("work", "cli")
+	empty := filepath.Join(root, "empty")
+	if err := os.Mkdir(empty, 0755); err != nil {
+		t.Fatal(err)
+	}
+	scope := func(paths []string, walk bool) string {
+		store, err := InitSearching(work, paths, walk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, option := range Lookup(store).Scopes {
+			for name := range option {
+				return name
+			}
+		}
+		return ""
+	}
+	if actual := scope([]string{empty, first, second}, true); actual != "api" {
+		t.Errorf("expected the first explicit path with a config to win, got %q", actual)
+	}
+	if actual := scope([]string{second, first}, false); actual != "web" {
+		t.Errorf("expected the paths' order to set the precedence, got %q", actual)
+	}
+	if actual := scope([]string{empty}, true); actual != "cli" {
+		t.Errorf("expected to fall back to the walk, got %q", actual)
+	}
+	if actual := scope([]string{empty}, false); actual != "" {
+		t.Errorf("expected no walk to leave only the defaults, got %q", actual)
+	}
+	if _, err := InitSearching(work, []string{filepath.Join(root, "missing")}, true); err == nil {
+		t.Error("expected a missing config path to be an error")
+	}
+	if _, err := InitSearching(work, []string{filepath.Join(first, "commit_convention.yml")}, true); err == nil {
+		t.Error("expected a file config path to be an error")
+	}
+}
+
+func TestSearchingWithoutHome(t *testing.T) {
+	root := t.TempDir()
+	work, xdg, cwd := filepath.Join(root, "work"), filepath.Join(root, "xdg"), filepath.Join(root, "cwd")
+	for _, dir := range []string{work, filepath.Join(xdg, "git-cc"), cwd} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dir := range []string{filepath.Join(xdg, "git-cc"), cwd} {
+		if err := os.WriteFile(filepath.Join(dir, "commit_convention.yml"), []byte("scopes: [x]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// an unset $HOME mustn't stand for the working directory
+	wd, _ := os.Getwd()
+	if err := os.Chdir(cwd); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("HOME", "")
+	os.Unsetenv("HOME")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	load := func() *viper.Viper {
+		store, err := InitSearching(work, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.ReadInConfig(); err != nil {
+			if _, notFound := err.(viper.ConfigFileNotFoundError); !notFound {
+				t.Fatal(err)
+			}
+		}
+		return store
+	}
+	if store := load(); store.ConfigFileUsed() != "" {
+		t.Errorf("expected no config file, got %s", store.ConfigFileUsed())
+	}
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if store := load(); store.ConfigFileUsed() != filepath.Join(xdg, "git-cc", "commit_convention.yml") {
+		t.Errorf("expected the XDG config file, got %q", store.ConfigFileUsed())
+	}
+	if err := os.WriteFile(filepath.Join(work, "commit_convention.yml"), []byte("scopes: [work]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if store := load(); store.ConfigFileUsed() != filepath.Join(work, "commit_convention.yml") {
+		t.Errorf("expected the working directory's config file, got %q", store.ConfigFileUsed())
+	}
+}
+
+func TestLineEndings(t *testing.T) {
+	defer func(original GitRunner) { Runner = original }(Runner)
+	for eol, expected := range map[string]string{"crlf\n": "\r\n", "lf\n": "\n"} {
+		Runner = fakeGitRunner{"config --get core.eol": eol}
+		if actual := LineEnding(); actual != expected {
+			t.Errorf("core.eol=%s: expected %q, got %q", strings.TrimSpace(eol), expected, actual)
+		}
+	}
+	mixed := "feat: x\n\nline one\r\nline two\rline three\n"
+	if actual := NormalizeLineEndings(mixed, "\n"); actual != "feat: x\n\nline one\nline two\nline three\n" {
+		t.Errorf("unexpected LF message %q", actual)
+	}
+	if actual := NormalizeLineEndings(mixed, "\r\n"); actual != "feat: x\r\n\r\nline one\r\nline two\r\nline three\r\n" {
+		t.Errorf("unexpected CRLF message %q", actual)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// the heading commit types without a changelog_sections entry are listed
+// under, unless changelog_hide_unmapped is set
+const DefaultChangelogSection = "Other Changes"
+
+// where a commit type's commits go in the changelog
+type ChangelogSection struct {
+	Section string `mapstructure:"section"` // the heading, e.g. "Features"
+	Hidden  bool   `mapstructure:"hidden"`  // whether to leave the type out
+	// the section's position; ties keep the order of the commit_types
+	Order int `mapstructure:"order"`
+}
+
+// the sections to use when changelog_sections is empty, matching
+// standard-version's defaults
+var DefaultChangelogSections = map[string]ChangelogSection{
+	"feat": {Section: "Features", Order: 1},
+	"fix":  {Section: "Bug Fixes", Order: 2},
+}
+
+// one of standard-version's `types`
+type ChangelogType struct {
+	Type    string `json:"type"`
+	Section string `json:"section,omitempty"`
+	Hidden  bool   `json:"hidden,omitempty"`
+}
+
+// check that each of changelog_sections' types is configured and that each
+// shown section has a heading.
+func (cfg Cfg) ValidateChangelogSections() error {
+	types := make([]string, 0, len(cfg.ChangelogSections))
+	for commitType := range cfg.ChangelogSections {
+		types = append(types, commitType)
+	}
+	sort.Strings(types)
+	for _, commitType := range types {
+		if !cfg.hasCommitType(commitType) {
+			return fmt.Errorf("changelog_sections: unknown commit type %q", commitType)
+		}
+		if section := cfg.ChangelogSections[commitType]; section.Section == "" && !section.Hidden {
+			return fmt.Errorf("changelog_sections: %q needs a section or hidden: true", commitType)
+		}
+	}
+	return nil
+}
+
+func (cfg Cfg) hasCommitType(commitType string) bool {
+	for _, option := range cfg.CommitTypes {
+		if _, ok := option[commitType]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// each commit type's changelog section: the mapped types by their order, then
+// the rest of the commit_types under the DefaultChangelogSection, or hidden.
+func (cfg Cfg) ChangelogTypes() []ChangelogType {
+	sections := cfg.ChangelogSections
+	if len(sections) == 0 {
+		sections = DefaultChangelogSections
+	}
+	mapped, unmapped := []string{}, []ChangelogType{}
+	for _, option := range cfg.CommitTypes {
+		for commitType := range option {
+			if _, ok := sections[commitType]; ok {
+				mapped = append(mapped, commitType)
+			} else if cfg.ChangelogHideUnmapped {
+				unmapped = append(unmapped, ChangelogType{Type: commitType, Hidden: true})
+			} else {
+				unmapped = append(unmapped, ChangelogType{Type: commitType, Section: DefaultChangelogSection})
+			}
+		}
+	}
+	sort.SliceStable(mapped, func(i, j int) bool {
+		return sections[mapped[i]].Order < sections[mapped[j]].Order
+	})
+	result := make([]ChangelogType, 0, len(mapped)+len(unmapped))
+	for _, commitType := range mapped {
+		section := sections[commitType]
+		result = append(result, ChangelogType{Type: commitType, Section: section.Section, Hidden: section.Hidden})
+	}
+	return append(result, unmapped...)
+}
+
+// a standard-version config, e.g. for a .versionrc.json, listing the
+// ChangelogTypes
+func (cfg Cfg) VersionRC() ([]byte, error) {
+	return json.MarshalIndent(map[string]interface{}{"types": cfg.ChangelogTypes()}, "", "  ")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidatingChangelogSections(t *testing.T) {
+	cfg := Cfg{CommitTypes: []map[string]string{{"feat": ""}, {"chore": ""}}}
+	cases := []struct {
+		sections map[string]ChangelogSection
+		valid    bool
+	}{
+		{map[string]ChangelogSection{}, true},
+		{map[string]ChangelogSection{"feat": {Section: "Features"}, "chore": {Hidden: true}}, true},
+		{map[string]ChangelogSection{"fix": {Section: "Bug Fixes"}}, false},
+		{map[string]ChangelogSection{"feat": {Order: 1}}, false},
+	}
+	for _, c := range cases {
+		cfg.ChangelogSections = c.sections
+		if err := cfg.ValidateChangelogSections(); (err == nil) != c.valid {
+			t.Errorf("%v: expected valid=%v, got %v", c.sections, c.valid, err)
+		}
+	}
+}
+
+func TestListingChangelogTypes(t *testing.T) {
+	cfg := Cfg{CommitTypes: []map[string]string{{"feat": ""}, {"fix": ""}, {"perf": ""}, {"chore": ""}}}
+	check := func(expected string) {
+		t.Helper()
+		actual, _ := json.Marshal(cfg.ChangelogTypes())
+		if string(actual) != expected {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+	check(`[{"type":"feat","section":"Features"},{"type":"fix","section":"Bug Fixes"},` +
+		`{"type":"perf","section":"Other Changes"},{"type":"chore","section":"Other Changes"}]`)
+	cfg.ChangelogSections = map[string]ChangelogSection{
+		"fix":   {Section: "Bug Fixes", Order: 1},
+		"perf":  {Section: "Performance"},
+		"chore": {Hidden: true, Order: 2},
+	}
+	cfg.ChangelogHideUnmapped = true
+	check(`[{"type":"perf","section":"Performance"},{"type":"fix","section":"Bug Fixes"},` +
+		`{"type":"chore","hidden":true},{"type":"feat","hidden":true}]`)
+}
+
+func TestReadingChangelogSections(t *testing.T) {
+	dir := t.TempDir()
+	contents := "changelog_sections:\n  feat: {section: Features, order: 1}\n  chore: {hidden: true}\n"
+	if err := os.WriteFile(filepath.Join(dir, "commit_convention.yml"), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Lookup(InitFrom(dir))
+	if section := cfg.ChangelogSections["feat"]; section.Section != "Features" || section.Order != 1 {
+		t.Errorf("expected feat's section, got %+v", section)
+	}
+	if !cfg.ChangelogSections["chore"].Hidden {
+		t.Errorf("expected chore to be hidden, got %+v", cfg.ChangelogSections)
+	}
+	if errs := ValidateAgainstSchema(map[string]interface{}{
+		"changelog_sections": map[string]interface{}{"feat": map[string]interface{}{"heading": "Features"}},
+	}); len(errs) == 0 {
+		t.Error("expected the schema to reject unknown section keys")
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// the keys of UI strings that can be translated
+const (
+	HelpSubmit    = "help.submit"
+	HelpBack      = "help.back"
+	HelpCancel    = "help.cancel"
+	HelpSelect    = "help.select"
+	HelpToggle    = "help.toggle"
+	HelpBreaking  = "help.breaking"
+	HelpDecline   = "help.decline"
+	HelpMove      = "help.move"
+	HelpRemove    = "help.remove"
+	ErrorRequired = "error.required"
+
+	// the footer review's labels
+	LabelFooters   = "label.footers"
+	LabelNoFooters = "label.no_footers"
+
+	// errors and warnings, as fmt formats; translations keep the verbs in order
+	ErrorUnknownType               = "error.unknown_type"
+	ErrorUnknownScope              = "error.unknown_scope"
+	ErrorInvalidType               = "error.invalid_type"
+	ErrorTypeNotOneWord            = "error.type_not_one_word"
+	ErrorTypeCase                  = "error.type_case"
+	ErrorScopeRequired             = "error.scope_required"
+	ErrorScopeNotAllowed           = "error.scope_not_allowed"
+	ErrorHeaderLength              = "error.header_length"
+	ErrorScopeLength               = "error.scope_length"
+	ErrorBodyLength                = "error.body_length"
+	ErrorFooterRequired            = "error.footer_required"
+	ErrorFooterRequiredJoin        = "error.footer_required_join"
+	ErrorWrongFooter               = "error.wrong_footer"
+	ErrorBreakingChangeDescription = "error.breaking_change_description"
+	ErrorBreakingFooterRemoval     = "error.breaking_footer_removal"
+	WarningPlaceholder             = "warning.placeholder"
+	WarningRevertRef               = "warning.revert_ref"
+	WarningFilterFailed            = "warning.filter_failed"
+	HintAllowedScopes              = "hint.allowed_scopes"
+	HintDescribeChange             = "hint.describe_change"
+	HintSubmitAgain                = "hint.submit_again"
+	HintSubmitToContinue           = "hint.submit_to_continue"
+	StatusOverLimit                = "status.over_limit"
+	StatusFiltering                = "status.filtering"
+)
+
+// maps message keys to the text to display
+type Catalog map[string]string
+
+// the default messages, which other languages fall back to
+var English = Catalog{
+	HelpSubmit:    "submit: tab/enter",
+	HelpBack:      "go back: shift+tab",
+	HelpCancel:    "cancel: ctrl+c",
+	HelpSelect:    "navigate: up/down/pgup/pgdn",
+	HelpToggle:    "toggle: left/right",
+	HelpBreaking:  "breaking: y",
+	HelpDecline:   "not breaking: n",
+	HelpMove:      "move: shift+up/shift+down",
+	HelpRemove:    "remove: d/delete",
+	ErrorRequired: "required",
+
+	LabelFooters:   "footers to commit:",
+	LabelNoFooters: "(none)",
+
+	ErrorUnknownType:               "unknown type '%s'",
+	ErrorUnknownScope:              "unknown scope '%s'",
+	ErrorInvalidType:               "invalid commit type '%s': %v",
+	ErrorTypeNotOneWord:            "commit type '%s' must be exactly one word",
+	ErrorTypeCase:                  "the type '%s' should be lower-case: use '%s'",
+	ErrorScopeRequired:             "'%s' commits need a scope",
+	ErrorScopeNotAllowed:           "'%s' commits can't have the scope '%s'",
+	ErrorHeaderLength:              "the header is %d characters long; the limit is %d",
+	ErrorScopeLength:               "the scope is %d characters long; the limit is %d",
+	ErrorBodyLength:                "the body is %d characters long; the limit is %d",
+	ErrorFooterRequired:            "'%s' commits need a %s footer",
+	ErrorFooterRequiredJoin:        " and a ",
+	ErrorWrongFooter:               "'%s' isn't a %s footer",
+	ErrorBreakingChangeDescription: "breaking changes need a `BREAKING CHANGE: <description>` footer",
+	ErrorBreakingFooterRemoval:     "change the breaking change in its own step",
+	WarningPlaceholder:             "'%s' looks like a placeholder",
+	WarningRevertRef:               "reverts usually reference the reverted commit, e.g. with a `Refs: <sha>` footer or --revert <sha>",
+	WarningFilterFailed:            "description_filter failed, keeping the description: %v",
+	HintAllowedScopes:              "scopes_by_type allows %s",
+	HintDescribeChange:             "describe what the commit changes",
+	HintSubmitAgain:                "submit again to commit anyway",
+	HintSubmitToContinue:           "submit again to continue",
+	StatusOverLimit:                "%d character(s) over the limit",
+	StatusFiltering:                "running the description_filter...",
+}
+
+func init() {
+	for key, prompt := range DefaultPrompts {
+		English["prompt."+key] = prompt
+	}
+}
+
+// the messages of the current language
+var messages = Catalog{}
+
+// the translation of `key` in the current language, falling back to English,
+// then to the key itself.
+func T(key string) string {
+	if msg, ok := messages[key]; ok {
+		return msg
+	}
+	if msg, ok := English[key]; ok {
+		return msg
+	}
+	return key
+}
+
+// the translation of `key`, formatted with `args` like fmt.Sprintf
+func Tf(key string, args ...interface{}) string {
+	return fmt.Sprintf(T(key), args...)
+}
+
+// the translation of `key`, formatted with `args` as an error
+func Error(key string, args ...interface{}) error {
+	return fmt.Errorf(T(key), args...)
+}
+
+// `err` followed by the translated `hint`, e.g. "...; submit again to commit
+// anyway"
+func WithHint(err error, hint string) error {
+	return fmt.Errorf("%w; %s", err, T(hint))
+}
+
+// reduce a locale like `de_DE.UTF-8` to its language, `de`
+func languageOf(locale string) string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_.@-"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "c" || lang == "posix" {
+		return "en"
+	}
+	return lang
+}
+
+// the language named by $GITCC_LANG, then $LANG, defaulting to English
+func LanguageFromEnv() string {
+	for _, env := range []string{"GITCC_LANG", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			return languageOf(locale)
+		}
+	}
+	return "en"
+}
+
+// where to look for `<lang>.yml` message catalogs
+func CatalogDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "git-cc", "lang")
+}
+
+// read a YAML message catalog, e.g. `help.submit: "absenden: tab/enter"`
+func LoadCatalog(path string) (Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog := Catalog{}
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// switch to the messages of `lang` from `dir`. Languages without a catalog
+// use English; returns an error if the catalog can't be read.
+func SetLanguage(dir string, lang string) error {
+	messages = Catalog{}
+	if lang == "en" || dir == "" {
+		return nil
+	}
+	catalog, err := LoadCatalog(filepath.Join(dir, lang+".yml"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	messages = catalog
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLanguageOf(t *testing.T) {
+	for locale, expected := range map[string]string{
+		"de_DE.UTF-8": "de",
+		"fr":          "fr",
+		"pt-BR":       "pt",
+		"C":           "en",
+		"POSIX":       "en",
+	} {
+		if actual := languageOf(locale); actual != expected {
+			t.Errorf("languageOf(%q) == %q, expected %q", locale, actual, expected)
+		}
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	dir := t.TempDir()
+	catalog := "help.submit: \"absenden: tab/enter\"\nprompt.scope: \"Bereich:\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "de.yml"), []byte(catalog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer SetLanguage("", "en")
+
+	if err := SetLanguage(dir, "de"); err != nil {
+		t.Fatal(err)
+	}
+	if actual := T(HelpSubmit); actual != "absenden: tab/enter" {
+		t.Errorf("expected the German help, got %q", actual)
+	}
+	if actual := T(HelpCancel); actual != English[HelpCancel] {
+		t.Errorf("expected missing keys to fall back to English, got %q", actual)
+	}
+	if actual := (Cfg{}).Prompt(PromptScope); actual != "Bereich:" {
+		t.Errorf("expected a translated default prompt, got %q", actual)
+	}
+	if actual := (Cfg{Prompts: map[string]string{PromptScope: "area:"}}).Prompt(PromptScope); actual != "area:" {
+		t.Errorf("expected the configured prompt to win, got %q", actual)
+	}
+
+	if err := SetLanguage(dir, "fr"); err != nil {
+		t.Errorf("expected a missing catalog to fall back to English, got %v", err)
+	}
+	if actual := T(HelpSubmit); actual != English[HelpSubmit] {
+		t.Errorf("expected English, got %q", actual)
+	}
+}
+
+func TestTranslatingErrors(t *testing.T) {
+	dir := t.TempDir()
+	catalog := "error.scope_required: \"'%s'-Commits brauchen einen Bereich\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "de.yml"), []byte(catalog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer SetLanguage("", "en")
+	if err := SetLanguage(dir, "de"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Cfg{RequireScopeFor: []string{"feat"}, HeaderMaxLength: 10}
+	if err := cfg.CheckScopeRequired("feat", ""); err == nil || err.Error() != "'feat'-Commits brauchen einen Bereich" {
+		t.Errorf("expected the German error, got %v", err)
+	}
+	if err := cfg.CheckHeaderLength("feat: too long"); err == nil || err.Error() != "the header is 14 characters long; the limit is 10" {
+		t.Errorf("expected missing errors to fall back to English, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+)
+
+// valid values for the subject_mood option
+const (
+	MoodAny        = "any"        // don't check the description's mood
+	MoodImperative = "imperative" // warn about descriptions like "added x"
+)
+
+// verbs that commonly start descriptions, in the imperative
+var imperativeVerbs = []string{
+	"add", "allow", "apply", "avoid", "bump", "change", "clarify", "clean",
+	"configure", "convert", "correct", "create", "delete", "deprecate",
+	"disable", "document", "drop", "enable", "ensure", "expose", "extract",
+	"fix", "handle", "ignore", "implement", "improve", "include", "initialize",
+	"install", "introduce", "load", "merge", "migrate", "move", "optimize",
+	"pass", "prevent", "refactor", "release", "remove", "rename", "reorder",
+	"replace", "require", "restore", "revert", "run", "simplify", "skip",
+	"sort", "split", "start", "stop", "support", "switch", "test", "tidy",
+	"tweak", "update", "upgrade", "use", "validate", "wrap",
+}
+
+// irregular past tenses of verbs that commonly start descriptions
+var irregularPastTenses = map[string]string{
+	"began":   "begin",
+	"broke":   "break",
+	"brought": "bring",
+	"built":   "build",
+	"chose":   "choose",
+	"did":     "do",
+	"found":   "find",
+	"gave":    "give",
+	"got":     "get",
+	"kept":    "keep",
+	"left":    "leave",
+	"made":    "make",
+	"ran":     "run",
+	"rebuilt": "rebuild",
+	"rewrote": "rewrite",
+	"took":    "take",
+	"undid":   "undo",
+	"wrote":   "write",
+}
+
+// past-tense and gerund forms -> the imperative, e.g. "added" -> "add"
+var imperatives = func() map[string]string {
+	result := map[string]string{}
+	for past, verb := range irregularPastTenses {
+		result[past] = verb
+		result[verb+"ing"] = verb
+	}
+	for _, verb := range imperativeVerbs {
+		stem, last := verb[:len(verb)-1], verb[len(verb)-1:]
+		switch {
+		case last == "e":
+			result[verb+"d"], result[stem+"ing"] = verb, verb
+		case last == "y" && !strings.ContainsAny(stem[len(stem)-1:], "aeiou"):
+			result[stem+"ied"], result[verb+"ing"] = verb, verb
+		default:
+			result[verb+"ed"], result[verb+"ing"] = verb, verb
+		}
+		// with a doubled final consonant, e.g. "stopped" or "running"
+		result[verb+last+"ed"], result[verb+last+"ing"] = verb, verb
+	}
+	return result
+}()
+
+// the first word of `description` and its imperative form if subject_mood is
+// imperative and the word is a past-tense or gerund form of a common verb,
+// e.g. "Added" and "add". Returns two empty strings otherwise. Verbs outside
+// the small list of common ones aren't recognized.
+func (cfg Cfg) MoodSuggestion(description string) (word string, imperative string) {
+	if cfg.SubjectMood != MoodImperative {
+		return "", ""
+	}
+	words := strings.FieldsFunc(description, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return "", ""
+	}
+	word = words[0]
+	if imperative = imperatives[strings.ToLower(word)]; imperative == "" {
+		return "", ""
+	}
+	return word, imperative
+}
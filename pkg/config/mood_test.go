@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestSuggestingTheImperative(t *testing.T) {
+	cfg := Cfg{SubjectMood: MoodImperative}
+	cases := map[string]string{
+		"added a flag":           "add",
+		"Adding a flag":          "add",
+		"fixed the parser":       "fix",
+		"updates the docs":       "",
+		"updated the docs":       "update",
+		"applied the patch":      "apply",
+		"stopped the spinner":    "stop",
+		"running hooks in order": "run",
+		"wrote the changelog":    "write",
+		"made it faster":         "make",
+		"using the cache":        "use",
+		"add a flag":             "",
+		"string parsing":         "",
+		"":                       "",
+		"...":                    "",
+		// accepted false negatives: verbs outside the list aren't recognized
+		"polished the prompts":  "",
+		"untangling the parser": "",
+	}
+	for description, expected := range cases {
+		if _, actual := cfg.MoodSuggestion(description); actual != expected {
+			t.Errorf("%q: expected %q, got %q", description, expected, actual)
+		}
+	}
+	cfg.SubjectMood = MoodAny
+	if word, _ := cfg.MoodSuggestion("added a flag"); word != "" {
+		t.Errorf("expected subject_mood: any to turn the check off, got %q", word)
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the default placeholder_descriptions: words that usually mean a commit
+// wasn't ready for a real description
+var DefaultPlaceholderDescriptions = []string{"wip", "tmp", "asdf", "fixup"}
+
+// the placeholder_descriptions entry that `description` starts with as a
+// whole word, ignoring case, or "" if there's none. "WIP: parser" and
+// "tmp fix" match "wip" and "tmp", but "wipe the cache" doesn't match "wip".
+func (cfg Cfg) Placeholder(description string) string {
+	description = strings.ToLower(strings.TrimSpace(description))
+	for _, placeholder := range cfg.PlaceholderDescriptions {
+		word := strings.ToLower(strings.TrimSpace(placeholder))
+		if word == "" || !strings.HasPrefix(description, word) {
+			continue
+		}
+		next, _ := utf8.DecodeRuneInString(description[len(word):])
+		if next == utf8.RuneError || !(unicode.IsLetter(next) || unicode.IsDigit(next)) {
+			return placeholder
+		}
+	}
+	return ""
+}
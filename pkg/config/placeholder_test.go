@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestMatchingPlaceholderDescriptions(t *testing.T) {
+	cfg := Cfg{PlaceholderDescriptions: DefaultPlaceholderDescriptions}
+	cases := map[string]string{
+		"wip":                    "wip",
+		"WIP: parser":            "wip",
+		"Tmp fix for the parser": "tmp",
+		"asdf":                   "asdf",
+		"fixup typo":             "fixup",
+		"wipe the cache":         "",
+		"add a tmpfile flag":     "",
+		"fixups for the parser":  "",
+		"add wip detection":      "",
+		"":                       "",
+	}
+	for description, expected := range cases {
+		if actual := cfg.Placeholder(description); actual != expected {
+			t.Errorf("%q: expected %q, got %q", description, expected, actual)
+		}
+	}
+	cfg.PlaceholderDescriptions = []string{}
+	if actual := cfg.Placeholder("wip"); actual != "" {
+		t.Errorf("expected an empty list to allow anything, got %q", actual)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// the keys of the `prompts` config section
+const (
+	PromptCommitType        = "commit_type"
+	PromptScope             = "scope"
+	PromptDescription       = "description"
+	PromptBreakingChange    = "breaking_change"
+	PromptBreakingChangeWhy = "breaking_change_explanation"
+	PromptRecentCommit      = "recent_commit"
+)
+
+// the prompts used unless the config overrides them
+var DefaultPrompts = map[string]string{
+	PromptCommitType:        "select a commit type: ",
+	PromptScope:             "select a scope:",
+	PromptDescription:       "A short description of the changes:",
+	PromptBreakingChange:    "Breaking change? ",
+	PromptBreakingChangeWhy: "Breaking changes: ",
+	PromptRecentCommit:      "select a commit to start from:",
+}
+
+// the configured prompt for `key`, falling back to the translated default
+func (cfg Cfg) Prompt(key string) string {
+	if prompt, ok := cfg.Prompts[key]; ok {
+		return prompt
+	}
+	return T("prompt." + key)
+}
+
+// returns an error if any of the `prompts` aren't known prompt keys
+func ValidatePrompts(prompts map[string]string) error {
+	unknown := []string{}
+	for key := range prompts {
+		if _, ok := DefaultPrompts[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	known := []string{}
+	for key := range DefaultPrompts {
+		known = append(known, key)
+	}
+	sort.Strings(unknown)
+	sort.Strings(known)
+	return fmt.Errorf(
+		"unknown prompts %s; expected any of %s",
+		strings.Join(unknown, ", "), strings.Join(known, ", "),
+	)
+}
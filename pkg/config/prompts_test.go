@@ -0,0 +1,19 @@
+package config
+
+import "testing"
+
+func TestConfiguringPrompts(t *testing.T) {
+	cfg := Cfg{Prompts: map[string]string{PromptScope: "welcher Bereich?"}}
+	if prompt := cfg.Prompt(PromptScope); prompt != "welcher Bereich?" {
+		t.Errorf("expected the configured prompt, got %q", prompt)
+	}
+	if prompt := cfg.Prompt(PromptCommitType); prompt != DefaultPrompts[PromptCommitType] {
+		t.Errorf("expected the default prompt, got %q", prompt)
+	}
+	if err := ValidatePrompts(cfg.Prompts); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidatePrompts(map[string]string{"scopes": "?"}); err == nil {
+		t.Error("expected unknown prompt keys to be rejected")
+	}
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// a JSON schema describing commit_convention.yml, for use by editors and
+// `git-cc config validate`.
+//
+//go:embed commit_convention.schema.json
+var Schema []byte
+
+// the subset of JSON schema used by commit_convention.schema.json
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Enum                 []interface{}      `json:"enum"`
+	Const                json.RawMessage    `json:"const"`
+	Minimum              *float64           `json:"minimum"`
+	MinLength            *int               `json:"minLength"`
+	Pattern              string             `json:"pattern"`
+	MinProperties        *int               `json:"minProperties"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	UniqueItems          bool               `json:"uniqueItems"`
+	Contains             *schema            `json:"contains"`
+	Definitions          map[string]*schema `json:"definitions"`
+	AnyOf                []*schema          `json:"anyOf"`
+	AllOf                []*schema          `json:"allOf"`
+}
+
+func loadSchema() *schema {
+	root := &schema{}
+	if err := json.Unmarshal(Schema, root); err != nil {
+		panic(fmt.Errorf("invalid embedded schema: %v", err))
+	}
+	return root
+}
+
+// resolve a local `$ref` such as `#` or `#/definitions/options`
+func (root *schema) resolve(s *schema) *schema {
+	switch {
+	case s.Ref == "#":
+		return root
+	case strings.HasPrefix(s.Ref, "#/definitions/"):
+		return root.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+	default:
+		return s
+	}
+}
+
+// a short description of what `s` matches, for errors
+func (s *schema) describe() string {
+	if s.Const != nil {
+		return string(s.Const)
+	}
+	if s.Type != "" {
+		return "type " + s.Type
+	}
+	return "the schema"
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func (root *schema) validate(s *schema, path string, value interface{}) []error {
+	s = root.resolve(s)
+	errs := []error{}
+	for _, requirement := range s.AllOf {
+		errs = append(errs, root.validate(requirement, path, value)...)
+	}
+	if s.AnyOf != nil {
+		for _, alternative := range s.AnyOf {
+			if len(root.validate(alternative, path, value)) == 0 {
+				return errs
+			}
+		}
+		return append(errs, fmt.Errorf("%s: %v matches none of the allowed forms", path, value))
+	}
+	if s.Const != nil {
+		var expected interface{}
+		json.Unmarshal(s.Const, &expected)
+		if fmt.Sprint(expected) != fmt.Sprint(value) {
+			return append(errs, fmt.Errorf("%s: expected %v, got %v", path, expected, value))
+		}
+	}
+	if s.Enum != nil {
+		for _, allowed := range s.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				return errs
+			}
+		}
+		return append(errs, fmt.Errorf("%s: %v is not one of %v", path, value, s.Enum))
+	}
+	if s.Type != "" && s.Type != typeOf(value) {
+		return append(errs, fmt.Errorf("%s: expected %s, got %s", path, s.Type, typeOf(value)))
+	}
+	if s.Minimum != nil && toFloat(value) < *s.Minimum {
+		errs = append(errs, fmt.Errorf("%s: %v is less than %v", path, value, *s.Minimum))
+	}
+	switch v := value.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(v)) < *s.MinLength {
+			errs = append(errs, fmt.Errorf("%s: expected at least %d character(s)", path, *s.MinLength))
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			errs = append(errs, fmt.Errorf("%s: %q doesn't match %s", path, v, s.Pattern))
+		}
+	case map[string]interface{}:
+		if s.MinProperties != nil && len(v) < *s.MinProperties {
+			errs = append(errs, fmt.Errorf("%s: expected at least %d key(s)", path, *s.MinProperties))
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := s.Properties[key]; ok {
+				errs = append(errs, root.validate(property, path+"."+key, v[key])...)
+				continue
+			}
+			switch string(s.AdditionalProperties) {
+			case "":
+			case "false":
+				errs = append(errs, fmt.Errorf("%s: unknown key %q", path, key))
+			default:
+				additional := &schema{}
+				json.Unmarshal(s.AdditionalProperties, additional)
+				errs = append(errs, root.validate(additional, path+"."+key, v[key])...)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, root.validate(s.Items, fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+		if s.UniqueItems {
+			seen := map[string]int{}
+			for i, item := range v {
+				if first, ok := seen[fmt.Sprint(item)]; ok {
+					errs = append(errs, fmt.Errorf("%s[%d]: %v repeats %s[%d]", path, i, item, path, first))
+				} else {
+					seen[fmt.Sprint(item)] = i
+				}
+			}
+		}
+		if s.Contains != nil {
+			found := false
+			for _, item := range v {
+				if len(root.validate(s.Contains, path, item)) == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				errs = append(errs, fmt.Errorf("%s: expected an item matching %s", path, s.Contains.describe()))
+			}
+		}
+	}
+	return errs
+}
+
+// check a deserialized config file against the Schema, returning an error for
+// each violation.
+func ValidateAgainstSchema(document interface{}) []error {
+	root := loadSchema()
+	return root.validate(root, "$", document)
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchemaDescribesEveryOption(t *testing.T) {
+	root := loadSchema()
+	fields := reflect.TypeOf(Cfg{})
+	for i := 0; i < fields.NumField(); i++ {
+		if !fields.Field(i).IsExported() {
+			continue // not read from the config file
+		}
+		key := fields.Field(i).Tag.Get("mapstructure")
+		if _, ok := root.Properties[key]; !ok {
+			t.Errorf("schema is missing the %q option", key)
+		}
+	}
+}
+
+func TestValidatingAgainstSchema(t *testing.T) {
+	test := func(document string, nErrors int) func(*testing.T) {
+		return func(t *testing.T) {
+			var parsed interface{}
+			if err := yaml.Unmarshal([]byte(document), &parsed); err != nil {
+				t.Fatal(err)
+			}
+			errs := ValidateAgainstSchema(parsed)
+			if len(errs) != nErrors {
+				t.Errorf("expected %d error(s), got %+v", nErrors, errs)
+			}
+		}
+	}
+	t.Run("accepts a valid config", test(`
+commit_types:
+  - feat: adds a feature
+scopes:
+  - cli: the command-line interface
+header_max_length: 50
+enforce_header_max_length: true
+subject_case: sentence
+steps: [description, commit_type]
+breaking_change_template: migrate X to Y
+profiles:
+  web:
+    scopes:
+      - ui: the ui
+`, 0))
+	t.Run("rejects unknown keys", test("scopez: []", 1))
+	t.Run("rejects wrong types", test("header_max_length: lots", 1))
+	t.Run("rejects negative lengths", test("header_max_length: -1", 1))
+	t.Run("rejects bad enum values", test("subject_case: title", 1))
+	t.Run("rejects non-string descriptions", test("scopes:\n  - cli: [1]", 1))
+	t.Run("validates profiles", test("profiles:\n  web:\n    subject_case: title", 1))
+	t.Run("rejects repeated steps", test("steps: [commit_type, scope, scope, description]", 1))
+	t.Run("rejects steps without a required one", test("steps: [commit_type, scope]", 1))
+	t.Run("rejects multi-line templates", test("breaking_change_template: \"a\\nb\"", 1))
+	t.Run("rejects an empty git command", test(`git_command: ""`, 1))
+	t.Run("rejects empty required footers", test(`required_footers: {fix: [""]}`, 1))
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// top-level directories that are never scopes
+var ignoredDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+var (
+	cargoMembers = regexp.MustCompile(`(?s)\[workspace\].*?members\s*=\s*\[(.*?)\]`)
+	quoted       = regexp.MustCompile(`"([^"]+)"`)
+	goWorkUse    = regexp.MustCompile(`(?m)^\s*use\s+(?:\(([^)]*)\)|(\S+))`)
+)
+
+// candidate scopes for the project at `root`: the members of a package.json,
+// Cargo.toml, or go.work workspace, or else the top-level directories. This
+// is best-effort; unreadable files are skipped.
+func DetectScopes(root string) []map[string]string {
+	for _, detect := range []func(string) ([]string, string){
+		packageJSONWorkspaces, cargoWorkspace, goWorkspace,
+	} {
+		if dirs, source := detect(root); len(dirs) > 0 {
+			return scopesFromDirs(root, dirs, "a member of the "+source+" workspace")
+		}
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	dirs := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && !strings.HasPrefix(name, ".") && !ignoredDirs[name] {
+			dirs = append(dirs, name)
+		}
+	}
+	return scopesFromDirs(root, dirs, "a top-level directory")
+}
+
+// expand the workspace `patterns` relative to `root` into distinct scopes
+// named after each directory.
+func scopesFromDirs(root string, patterns []string, hint string) []map[string]string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		for _, match := range matches {
+			name := filepath.Base(match)
+			if info, err := os.Stat(match); err == nil && info.IsDir() && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	scopes := []map[string]string{}
+	for _, name := range names {
+		scopes = append(scopes, map[string]string{name: hint})
+	}
+	return scopes
+}
+
+func packageJSONWorkspaces(root string) ([]string, string) {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil, ""
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || pkg.Workspaces == nil {
+		return nil, ""
+	}
+	var patterns []string
+	if json.Unmarshal(pkg.Workspaces, &patterns) != nil {
+		// yarn's {"packages": [...]} form
+		var nested struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(pkg.Workspaces, &nested) != nil {
+			return nil, ""
+		}
+		patterns = nested.Packages
+	}
+	return patterns, "package.json"
+}
+
+func cargoWorkspace(root string) ([]string, string) {
+	data, err := os.ReadFile(filepath.Join(root, "Cargo.toml"))
+	if err != nil {
+		return nil, ""
+	}
+	match := cargoMembers.FindSubmatch(data)
+	if match == nil {
+		return nil, ""
+	}
+	patterns := []string{}
+	for _, member := range quoted.FindAllSubmatch(match[1], -1) {
+		patterns = append(patterns, string(member[1]))
+	}
+	return patterns, "Cargo.toml"
+}
+
+func goWorkspace(root string) ([]string, string) {
+	data, err := os.ReadFile(filepath.Join(root, "go.work"))
+	if err != nil {
+		return nil, ""
+	}
+	patterns := []string{}
+	for _, match := range goWorkUse.FindAllStringSubmatch(string(data), -1) {
+		for _, dir := range strings.Fields(match[1] + " " + match[2]) {
+			if dir != "." && !strings.HasPrefix(dir, "//") {
+				patterns = append(patterns, dir)
+			}
+		}
+	}
+	return patterns, "go.work"
+}
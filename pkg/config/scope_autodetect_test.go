@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectingScopes(t *testing.T) {
+	test := func(files map[string]string, dirs []string, expected []string) func(*testing.T) {
+		return func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range dirs {
+				if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			actual := []string{}
+			for _, scope := range DetectScopes(root) {
+				for name := range scope {
+					actual = append(actual, name)
+				}
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %v, got %v", expected, actual)
+			}
+		}
+	}
+	t.Run("package.json", test(
+		map[string]string{"package.json": `{"workspaces": ["packages/*"]}`},
+		[]string{"packages/ui", "packages/api", "docs"}, []string{"api", "ui"},
+	))
+	t.Run("yarn packages", test(
+		map[string]string{"package.json": `{"workspaces": {"packages": ["apps/web"]}}`},
+		[]string{"apps/web", "apps/cli"}, []string{"web"},
+	))
+	t.Run("Cargo.toml", test(
+		map[string]string{"Cargo.toml": "[workspace]\nmembers = [\n  \"crates/*\",\n]\n"},
+		[]string{"crates/core", "crates/cli"}, []string{"cli", "core"},
+	))
+	t.Run("go.work", test(
+		map[string]string{"go.work": "go 1.19\n\nuse (\n\t.\n\t./tools\n)\nuse ./server\n"},
+		[]string{"tools", "server", "docs"}, []string{"server", "tools"},
+	))
+	t.Run("top-level directories", test(
+		map[string]string{"README.md": ""},
+		[]string{"cmd", "pkg", ".github", "node_modules"}, []string{"cmd", "pkg"},
+	))
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+// orderings of the scope selector's options
+const (
+	ScopeSortConfig  = "config"  // as listed in the config file
+	ScopeSortAlpha   = "alpha"   // alphabetically
+	ScopeSortRecency = "recency" // most recently committed first
+)
+
+// how many commits to scan for recently-used scopes
+const recencyDepth = 1000
+
+var (
+	recentScopes     []string
+	recentScopesOnce sync.Once
+)
+
+// the distinct scopes of recent commits parsed with `opts`, most recent first.
+// The history is only scanned once per session.
+func RecentScopes(opts ...parser.Option) []string {
+	recentScopesOnce.Do(func() {
+		out, err := Runner.Output("log", "--format=%s", "-n", strconv.Itoa(recencyDepth))
+		if err != nil {
+			return // e.g. no commits yet
+		}
+		recentScopes = scopesOf(strings.Split(out, "\n"), opts...)
+	})
+	return recentScopes
+}
+
+// the distinct scopes of each commit header, in order of first appearance
+func scopesOf(headers []string, opts ...parser.Option) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, header := range headers {
+		cc, _ := parser.ParseHeader(header, opts...)
+		if cc.Scope != "" && !seen[cc.Scope] {
+			seen[cc.Scope] = true
+			result = append(result, cc.Scope)
+		}
+	}
+	return result
+}
+
+// sort the scope options per `mode`. In recency order, scopes in `recent`
+// come first and the rest keep their configured order.
+func SortScopes(scopes []map[string]string, mode string, recent []string) []map[string]string {
+	result := append([]map[string]string{}, scopes...)
+	nameOf := func(option map[string]string) string {
+		for name := range option {
+			return name
+		}
+		return ""
+	}
+	switch mode {
+	case ScopeSortAlpha:
+		sort.SliceStable(result, func(i, j int) bool {
+			return nameOf(result[i]) < nameOf(result[j])
+		})
+	case ScopeSortRecency:
+		rank := map[string]int{}
+		for i, scope := range recent {
+			rank[scope] = i + 1
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			a, b := rank[nameOf(result[i])], rank[nameOf(result[j])]
+			return a != 0 && (b == 0 || a < b)
+		})
+	}
+	return result
+}
+
+// the scopes in the configured order
+func (cfg Cfg) SortedScopes() []map[string]string {
+	var recent []string
+	if cfg.ScopeSort == ScopeSortRecency {
+		recent = RecentScopes(cfg.ParserOption())
+	}
+	return SortScopes(cfg.Scopes, cfg.ScopeSort, recent)
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSortingScopes(t *testing.T) {
+	scopes := []map[string]string{{"parser": ""}, {"cli": ""}, {"tui": ""}, {"docs": ""}}
+	recent := scopesOf([]string{
+		"fix(tui): x", "feat(cli): y", "docs: z", "fix(tui): w", "chore(unconfigured): v",
+	})
+	if fmt.Sprint(recent) != "[tui cli unconfigured]" {
+		t.Fatalf("unexpected recent scopes %v", recent)
+	}
+	test := func(mode string, recent []string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			if actual := fmt.Sprint(SortScopes(scopes, mode, recent)); actual != expected {
+				t.Errorf("expected %s, got %s", expected, actual)
+			}
+		}
+	}
+	t.Run("config", test(ScopeSortConfig, recent, "[map[parser:] map[cli:] map[tui:] map[docs:]]"))
+	t.Run("alpha", test(ScopeSortAlpha, recent, "[map[cli:] map[docs:] map[parser:] map[tui:]]"))
+	t.Run("recency", test(ScopeSortRecency, recent, "[map[tui:] map[cli:] map[parser:] map[docs:]]"))
+	t.Run("recency without history", test(ScopeSortRecency, nil, "[map[parser:] map[cli:] map[tui:] map[docs:]]"))
+}
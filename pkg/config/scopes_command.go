@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	commandScopes     = map[string][]map[string]string{}
+	commandScopesLock sync.Mutex
+)
+
+// the scopes printed by the shell command `command`, one per line and
+// optionally as `scope: description`. Each command only runs once per
+// session.
+func CommandScopes(command string) ([]map[string]string, error) {
+	commandScopesLock.Lock()
+	defer commandScopesLock.Unlock()
+	if scopes, ok := commandScopes[command]; ok {
+		return scopes, nil
+	}
+	out, err := runShell(command, "")
+	if err != nil {
+		return nil, err
+	}
+	scopes := parseScopes(out)
+	commandScopes[command] = scopes
+	return scopes, nil
+}
+
+// parse lines of `scope` or `scope: description`, skipping blank lines
+func parseScopes(out string) []map[string]string {
+	scopes := []map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		scope, description, _ := strings.Cut(line, ":")
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		scopes = append(scopes, map[string]string{scope: strings.TrimSpace(description)})
+	}
+	return scopes
+}
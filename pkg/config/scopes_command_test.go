@@ -0,0 +1,29 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCommandScopes(t *testing.T) {
+	scopes, err := CommandScopes(`printf 'api: the REST API\n\nui\n'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]string{{"api": "the REST API"}, {"ui": ""}}
+	if !reflect.DeepEqual(scopes, expected) {
+		t.Errorf("expected %v, got %v", expected, scopes)
+	}
+}
+
+func TestCommandScopesFailure(t *testing.T) {
+	if _, err := CommandScopes("exit 3"); err == nil {
+		t.Error("expected a failing command to return an error")
+	}
+	defer func(timeout time.Duration) { CommandTimeout = timeout }(CommandTimeout)
+	CommandTimeout = 50 * time.Millisecond
+	if _, err := CommandScopes("sleep 5"); err == nil {
+		t.Error("expected a slow command to time out")
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// how long a configured command like scopes_command may run before it's
+// abandoned
+var CommandTimeout = 5 * time.Second
+
+// run the shell command `command` with `stdin`, returning its stdout.
+func runShell(command string, stdin string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	var out, errOut bytes.Buffer
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("`%s`: %w", command, err)
+	}
+	done := make(chan error, 1)
+	// the shell's children can hold stdout open after the shell is killed, so
+	// don't wait for them once the command times out.
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if stderr := strings.TrimSpace(errOut.String()); err != nil && stderr != "" {
+			return "", fmt.Errorf("`%s`: %w:\n%s", command, err, stderr)
+		} else if err != nil {
+			return "", fmt.Errorf("`%s`: %w", command, err)
+		}
+		return out.String(), nil
+	case <-time.After(CommandTimeout):
+		_ = cmd.Process.Kill()
+		return "", fmt.Errorf("`%s` timed out after %s", command, CommandTimeout)
+	}
+}
+
+// pass `description` through the description_filter, if any. The filter's
+// first line of output replaces the description unless it's blank.
+func (cfg Cfg) FilterDescription(description string) (string, error) {
+	if cfg.DescriptionFilter == "" {
+		return description, nil
+	}
+	out, err := runShell(cfg.DescriptionFilter, description)
+	if err != nil {
+		return description, err
+	}
+	filtered := strings.TrimSpace(strings.SplitN(strings.TrimLeft(out, "\r\n"), "\n", 2)[0])
+	if filtered == "" {
+		return description, nil
+	}
+	return filtered, nil
+}
+
+// pass the whole `message` through the message_filter, if any, returning its
+// output. Unlike FilterDescription, a failing filter or one that prints
+// nothing is an error, which should abort the commit.
+func (cfg Cfg) FilterMessage(message string) (string, error) {
+	if cfg.MessageFilter == "" {
+		return message, nil
+	}
+	out, err := runShell(cfg.MessageFilter, message)
+	if err != nil {
+		return "", fmt.Errorf("message_filter failed: %w", err)
+	}
+	if strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("message_filter `%s` printed no message", cfg.MessageFilter)
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilteringTheDescription(t *testing.T) {
+	test := func(filter string, expected string, fails bool) func(*testing.T) {
+		return func(t *testing.T) {
+			actual, err := Cfg{DescriptionFilter: filter}.FilterDescription("fix the typo")
+			if actual != expected || (err != nil) != fails {
+				t.Errorf("expected %q (failing: %v), got %q, %v", expected, fails, actual, err)
+			}
+		}
+	}
+	t.Run("no filter", test("", "fix the typo", false))
+	t.Run("uppercasing", test("tr a-z A-Z", "FIX THE TYPO", false))
+	t.Run("multiple lines", test("cat; echo; echo more", "fix the typo", false))
+	t.Run("first of multiple lines", test("echo fixed; echo more", "fixed", false))
+	t.Run("empty output", test("true", "fix the typo", false))
+	t.Run("failing", test("exit 1", "fix the typo", true))
+}
+
+func TestFilteringTheMessage(t *testing.T) {
+	message := "feat: x\n\nRefs: #1\n"
+	test := func(filter string, expected string, fails bool) func(*testing.T) {
+		return func(t *testing.T) {
+			actual, err := Cfg{MessageFilter: filter}.FilterMessage(message)
+			if actual != expected || (err != nil) != fails {
+				t.Errorf("expected %q (failing: %v), got %q, %v", expected, fails, actual, err)
+			}
+		}
+	}
+	t.Run("no filter", test("", message, false))
+	t.Run("identity", test("cat", message, false))
+	t.Run("adding a trailer", test("cat; echo 'Reviewed-by: ci'", message+"Reviewed-by: ci\n", false))
+	t.Run("empty output", test("true", "", true))
+	t.Run("failing", test("echo ok; exit 1", "", true))
+	_, err := Cfg{MessageFilter: "echo 'needs a ticket' >&2; exit 1"}.FilterMessage(message)
+	if err == nil || !strings.Contains(err.Error(), "needs a ticket") {
+		t.Errorf("expected the filter's stderr in the error, got %v", err)
+	}
+}
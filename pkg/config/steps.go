@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the steps of the TUI, named like their prompts
+const (
+	StepCommitType     = PromptCommitType
+	StepScope          = PromptScope
+	StepDescription    = PromptDescription
+	StepBreakingChange = PromptBreakingChange
+)
+
+// every step, in the default order
+var DefaultSteps = []string{StepCommitType, StepScope, StepDescription, StepBreakingChange}
+
+// returns an error unless `steps` are distinct, known steps including the
+// required commit_type and description steps.
+func ValidateSteps(steps []string) error {
+	seen := map[string]bool{}
+	for _, step := range steps {
+		known := false
+		for _, name := range DefaultSteps {
+			known = known || step == name
+		}
+		if !known {
+			return fmt.Errorf(
+				"unknown step %q; expected any of %s", step, strings.Join(DefaultSteps, ", "),
+			)
+		}
+		if seen[step] {
+			return fmt.Errorf("step %q is listed more than once", step)
+		}
+		seen[step] = true
+	}
+	for _, required := range []string{StepCommitType, StepDescription} {
+		if !seen[required] {
+			return fmt.Errorf("steps must include %q", required)
+		}
+	}
+	return nil
+}
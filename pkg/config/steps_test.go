@@ -0,0 +1,25 @@
+package config
+
+import "testing"
+
+func TestValidatingSteps(t *testing.T) {
+	for _, steps := range [][]string{
+		DefaultSteps,
+		{StepDescription, StepCommitType},
+		{StepCommitType, StepBreakingChange, StepDescription},
+	} {
+		if err := ValidateSteps(steps); err != nil {
+			t.Errorf("expected %v to be valid, got %v", steps, err)
+		}
+	}
+	for _, steps := range [][]string{
+		{StepCommitType, StepScope},
+		{StepScope, StepDescription},
+		{StepCommitType, StepDescription, "body"},
+		{StepCommitType, StepDescription, StepCommitType},
+	} {
+		if err := ValidateSteps(steps); err == nil {
+			t.Errorf("expected %v to be invalid", steps)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+// the profile every UI string is styled with: plain ASCII on terminals that
+// can't show styles, e.g. TERM=dumb, and ANSI otherwise.
+var ColorProfile termenv.Profile
+
+func init() {
+	SetColorProfile(DetectColorProfile())
+}
+
+// style UI strings with `profile`. Ascii also turns off the styles of the
+// bubbles components, like the reversed cursor of text inputs.
+func SetColorProfile(profile termenv.Profile) {
+	ColorProfile = profile
+	if profile == termenv.Ascii {
+		lipgloss.SetColorProfile(profile)
+	}
+}
+
+// whether the terminal can show styles, per $TERM, $NO_COLOR, and the locale
+func DetectColorProfile() termenv.Profile {
+	if os.Getenv("TERM") == "dumb" || os.Getenv("NO_COLOR") != "" || !utf8Locale() {
+		return termenv.Ascii
+	}
+	return termenv.ANSI
+}
+
+// whether the locale's character set is UTF-8, taking the first of $LC_ALL,
+// $LC_CTYPE, and $LANG that's set. Without any, e.g. on Windows, assume it is.
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// `s`, ready to be styled per the ColorProfile
+func Style(s string) termenv.Style {
+	return ColorProfile.String(s)
+}
+
+func Faint(s string) string {
+	return Style(s).Faint().String()
+}
+
+func Underline(s string) string {
+	return Style(s).Underline().String()
+}
+
+// how many terminal columns `s` takes up: 2 for most CJK characters and emoji,
+// 0 for combining marks, and 1 otherwise.
+func Width(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// a text input whose cursor follows the ColorProfile: terminals without
+// styles can't show the reversed cursor, so it's hidden.
+func NewTextInput() textinput.Model {
+	input := textinput.New()
+	if ColorProfile == termenv.Ascii {
+		input.SetCursorMode(textinput.CursorHide)
+	}
+	return input
+}
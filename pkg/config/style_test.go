@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestDetectingColorProfilesFromTheLocale(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("NO_COLOR", "")
+	for _, c := range []struct {
+		lcAll, lcCtype, lang string
+		expected             termenv.Profile
+	}{
+		{"", "", "en_US.UTF-8", termenv.ANSI},
+		{"", "", "de_DE.utf8", termenv.ANSI},
+		{"", "", "", termenv.ANSI},
+		{"", "", "C", termenv.Ascii},
+		{"", "en_US.ISO-8859-1", "en_US.UTF-8", termenv.Ascii},
+		{"POSIX", "en_US.UTF-8", "en_US.UTF-8", termenv.Ascii},
+		{"C.UTF-8", "", "C", termenv.ANSI},
+	} {
+		t.Setenv("LC_ALL", c.lcAll)
+		t.Setenv("LC_CTYPE", c.lcCtype)
+		t.Setenv("LANG", c.lang)
+		if actual := DetectColorProfile(); actual != c.expected {
+			t.Errorf("expected %v with LC_ALL=%q LC_CTYPE=%q LANG=%q, got %v",
+				c.expected, c.lcAll, c.lcCtype, c.lang, actual)
+		}
+	}
+}
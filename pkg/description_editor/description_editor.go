@@ -8,21 +8,24 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
 	"github.com/muesli/reflow/ansi"
 	"github.com/muesli/reflow/wordwrap"
-	"github.com/muesli/termenv"
 	"github.com/skalt/git-cc/pkg/config"
 	"github.com/skalt/git-cc/pkg/helpbar"
 )
 
-const prePrompt = "A short description of the changes:"
-
 type Model struct {
 	width       int
 	input       textinput.Model // TODO: make input a pointer
 	lengthLimit int             // TODO: make *int and use nil to eliminate countdown
 	helpBar     helpbar.Model
 	prefix      string
+	suffix      string // e.g. ` (scope)` in the scope-last layout
+	prompt      string // shown above the input
+	ruler       bool   // whether to mark the length limit under the input
+	// a faint, non-blocking note about the current value, if any
+	hint func(value string) string
 }
 
 func (m Model) SetPrefix(prefix string) Model {
@@ -30,6 +33,22 @@ func (m Model) SetPrefix(prefix string) Model {
 	m.input.Prompt = prefix
 	return m
 }
+func (m Model) SetSuffix(suffix string) Model {
+	m.suffix = suffix
+	return m
+}
+func (m Model) SetPrompt(prompt string) Model {
+	m.prompt = prompt
+	return m
+}
+func (m Model) SetRuler(ruler bool) Model {
+	m.ruler = ruler
+	return m
+}
+func (m Model) SetHint(hint func(value string) string) Model {
+	m.hint = hint
+	return m
+}
 func (m Model) SetErr(err error) Model {
 	m.input.Err = err
 	return m
@@ -41,19 +60,29 @@ func (m Model) Focus() tea.Cmd {
 func (m Model) Value() string {
 	return m.input.Value()
 }
+func (m Model) SetValue(value string) Model {
+	m.input.SetValue(value)
+	return m
+}
+
+// whether typing stops at the length limit
+func (m Model) Enforced() bool {
+	return m.input.CharLimit > 0
+}
 
 func NewModel(lengthLimit int, value string, enforced bool) Model {
-	input := textinput.NewModel()
+	input := config.NewTextInput()
 	input.SetValue(value)
 	input.SetCursor(len(value))
 	// input.Cursor = len(value)
-	input.Prompt = config.Faint(prePrompt)
+	input.Prompt = config.Faint(config.DefaultPrompts[config.PromptDescription])
 	if enforced {
 		input.CharLimit = lengthLimit
 	}
 	input.Focus()
 	return Model{
 		lengthLimit: lengthLimit,
+		prompt:      config.DefaultPrompts[config.PromptDescription],
 		input:       input,
 		helpBar: helpbar.NewModel(
 			config.HelpSubmit,
@@ -63,9 +92,15 @@ func NewModel(lengthLimit int, value string, enforced bool) Model {
 	}
 }
 
+// the header's width in terminal columns, which is what git log and the
+// length limit count
+func (m Model) headerWidth() int {
+	return config.Width(m.prefix + m.input.Value() + m.suffix)
+}
+
 // a styled length-counter, e.g. ( 9/80)
 func viewCounter(m Model) string {
-	current := len(m.prefix) + len(m.input.Value())
+	current := m.headerWidth()
 	paddedFormat := fmt.Sprintf(
 		"(%%%dd/%d)", len(fmt.Sprintf("%d", m.lengthLimit)), m.lengthLimit,
 	)
@@ -75,8 +110,57 @@ func viewCounter(m Model) string {
 	} else if current == m.lengthLimit {
 		return view // render in a warning color termenv.String(view).
 	} else { // render in an alert color
-		return termenv.String(view).Underline().String()
+		return config.Underline(view)
+	}
+}
+
+// stands in for the commit's abbreviated hash in the oneline preview
+const shortShaPlaceholder = "1234abc"
+
+// the width to preview at before the terminal's width is known
+const defaultWidth = 80
+
+// `header` as `git log --oneline` would show it in a terminal `width` columns
+// wide: after the short hash, and cut off with ".." if it doesn't fit. Wide
+// characters such as CJK and emoji take up two columns.
+func oneline(header string, width int) string {
+	line := shortShaPlaceholder + " " + header
+	if config.Width(line) <= width {
+		return line
+	}
+	if width < 2 {
+		return runewidth.Truncate(line, width, "")
+	}
+	return runewidth.Truncate(line, width, "..")
+}
+
+// a ruler marking the first column past `limit`, which lines up with the
+// input since the input starts with the header's prefix. It's cut off at
+// `width` if that's known.
+func ruler(limit int, width int) string {
+	line := strings.Repeat("-", limit) + "|"
+	if width > 0 && len(line) > width {
+		line = line[:width]
+	}
+	return line
+}
+
+// drop the runes just typed or pasted before the cursor until the header fits
+// within the length limit, given that the description was `previous` runes
+// long before.
+func (m Model) fit(previous int) Model {
+	value, cursor := []rune(m.input.Value()), m.input.Cursor()
+	if m.headerWidth() <= m.lengthLimit {
+		return m
 	}
+	for len(value) > previous && cursor > 0 &&
+		config.Width(m.prefix+string(value)+m.suffix) > m.lengthLimit {
+		value = append(value[:cursor-1], value[cursor:]...)
+		cursor--
+	}
+	m.input.SetValue(string(value))
+	m.input.SetCursor(cursor)
+	return m
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
@@ -88,7 +172,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case tea.KeyCtrlC, tea.KeyCtrlD:
 			return m, tea.Quit
 		default:
+			m.input.Err = nil
+			previous := len([]rune(m.input.Value()))
 			m.input, cmd = m.input.Update(msg)
+			if m.Enforced() {
+				m = m.fit(previous)
+			}
 			m.input.Focus()
 			return m, cmd
 		}
@@ -106,10 +195,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 func (m Model) View() string {
 	s := strings.Builder{}
-	s.WriteString(wordwrap.String(config.Faint(prePrompt), m.width))
+	s.WriteString(wordwrap.String(config.Faint(m.prompt), m.width))
 	s.WriteRune('\n')
 	s.WriteRune('\n')
 	s.WriteString(m.input.View())
+	s.WriteString(config.Faint(m.suffix))
+	s.WriteRune('\n')
+	if m.ruler && m.lengthLimit > 0 {
+		s.WriteString(config.Faint(ruler(m.lengthLimit, m.width)))
+		s.WriteRune('\n')
+	}
+	if m.input.Err != nil {
+		s.WriteString(config.Underline(m.input.Err.Error()))
+		s.WriteRune('\n')
+	} else if m.hint != nil {
+		if hint := m.hint(m.input.Value()); hint != "" {
+			s.WriteString(config.Faint(hint))
+			s.WriteRune('\n')
+		}
+	}
+	width := m.width
+	if width == 0 {
+		width = defaultWidth
+	}
+	s.WriteString(config.Faint(oneline(m.prefix+m.input.Value()+m.suffix, width)))
 	s.WriteRune('\n')
 	s.WriteRune('\n')
 	helpBar := m.helpBar.View()
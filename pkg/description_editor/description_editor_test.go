@@ -0,0 +1,83 @@
+package description_editor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOneline(t *testing.T) {
+	header := "feat(cli): add a preview"
+	if actual := oneline(header, 80); actual != "1234abc feat(cli): add a preview" {
+		t.Errorf("expected the header after the placeholder hash, got %q", actual)
+	}
+	if actual := oneline(header, 20); actual != "1234abc feat(cli):.." {
+		t.Errorf("expected a truncated header, got %q", actual)
+	}
+}
+
+func TestRulingTheLengthLimit(t *testing.T) {
+	if actual := ruler(10, 0); actual != "----------|" {
+		t.Errorf("expected a mark past the 10th column, got %q", actual)
+	}
+	if actual := ruler(10, 5); actual != "-----" {
+		t.Errorf("expected the ruler to fit the terminal, got %q", actual)
+	}
+	m := NewModel(10, "x", false).SetPrefix("feat: ")
+	if strings.Contains(m.View(), "|") {
+		t.Errorf("expected no ruler unless enabled, got %q", m.View())
+	}
+	if view := m.SetRuler(true).View(); !strings.Contains(view, "----------|") {
+		t.Errorf("expected a ruler, got %q", view)
+	}
+}
+
+func TestMeasuringWideCharacters(t *testing.T) {
+	// each of these takes up two columns
+	header := "feat: 添加预览 🎉"
+	if actual := oneline(header, 80); actual != "1234abc "+header {
+		t.Errorf("expected the whole header, got %q", actual)
+	}
+	if actual := oneline(header, 18); actual != "1234abc feat: 添.." {
+		t.Errorf("expected the header cut at 18 columns, got %q", actual)
+	}
+	m := NewModel(20, "添加预览 🎉", false).SetPrefix("feat: ")
+	if counter := viewCounter(m); !strings.Contains(counter, "(17/20)") {
+		t.Errorf("expected the counter to count columns, got %q", counter)
+	}
+}
+
+func TestEnforcingTheLimitInColumns(t *testing.T) {
+	m := NewModel(12, "", true).SetPrefix("feat: ")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("添加预览")})
+	if value := m.Value(); value != "添加预" {
+		t.Errorf("expected the description to stop at 12 columns, got %q", value)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("🎉")})
+	if value := m.Value(); value != "添加预" {
+		t.Errorf("expected no room for an emoji, got %q", value)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ab")})
+	if value := m.Value(); value != "添加ab" {
+		t.Errorf("expected narrow characters to fill the rest, got %q", value)
+	}
+}
+
+func TestShowingAHint(t *testing.T) {
+	m := NewModel(72, "added", false).SetHint(func(value string) string {
+		if value == "added" {
+			return "consider 'add'"
+		}
+		return ""
+	})
+	if view := m.View(); !strings.Contains(view, "consider 'add'") {
+		t.Errorf("expected the hint, got %q", view)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if view := m.View(); strings.Contains(view, "consider") {
+		t.Errorf("expected the hint to follow the value, got %q", view)
+	}
+}
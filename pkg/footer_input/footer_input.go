@@ -0,0 +1,67 @@
+package footer_input
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/helpbar"
+)
+
+// asks for the value of a footer whose token is already known, e.g. a `Refs:`
+// footer that required_footers needs.
+type Model struct {
+	token    string
+	question string
+	input    textinput.Model
+	helpBar  helpbar.Model
+}
+
+func NewModel(token string, question string) Model {
+	input := config.NewTextInput()
+	input.Prompt = token + ": "
+	input.Focus()
+	return Model{
+		token:    token,
+		question: question,
+		input:    input,
+		helpBar:  helpbar.NewModel(config.HelpSubmit, config.HelpBack, config.HelpCancel),
+	}
+}
+
+// the token the footer is asked for
+func (m Model) Token() string {
+	return m.token
+}
+
+// the footer as it'll be written, e.g. `Refs: #1`
+func (m Model) Value() string {
+	return m.token + ": " + strings.TrimSpace(m.input.Value())
+}
+
+func (m Model) SetErr(err error) Model {
+	m.input.Err = err
+	return m
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.(type) {
+	case tea.KeyMsg:
+		m.input.Err = nil
+	case tea.WindowSizeMsg:
+		m.helpBar, _ = m.helpBar.Update(msg)
+		return m, cmd
+	}
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	view := config.Faint(m.question) + "\n" + m.input.View()
+	if m.input.Err != nil {
+		view += "\n" + config.Underline(m.input.Err.Error())
+	}
+	return view + "\n\n" + m.helpBar.View() + "\n"
+}
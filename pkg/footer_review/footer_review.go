@@ -0,0 +1,118 @@
+package footer_review
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/helpbar"
+)
+
+// lists the footers a commit will have so that they can be removed or
+// reordered before committing.
+type Model struct {
+	footers []string
+	cursor  int
+	// an error if `footer` mustn't be removed, leaving `remaining`
+	canRemove func(remaining []string, footer string) error
+	err       error
+	helpBar   helpbar.Model
+}
+
+func NewModel(footers []string, canRemove func(remaining []string, footer string) error) Model {
+	return Model{
+		footers:   append([]string{}, footers...),
+		canRemove: canRemove,
+		helpBar: helpbar.NewModel(
+			config.HelpSelect, config.HelpMove, config.HelpRemove,
+			config.HelpSubmit, config.HelpBack, config.HelpCancel,
+		),
+	}
+}
+
+// the footers in their current order
+func (m Model) Footers() []string {
+	return append([]string{}, m.footers...)
+}
+
+// the footers as they'll be written
+func (m Model) Value() string {
+	return strings.Join(m.footers, "\n")
+}
+
+func (m Model) SetErr(err error) Model {
+	m.err = err
+	return m
+}
+
+// swap the footer under the cursor with its neighbor `by` places away
+func (m Model) move(by int) Model {
+	next := m.cursor + by
+	if next < 0 || next >= len(m.footers) {
+		return m
+	}
+	m.footers[m.cursor], m.footers[next] = m.footers[next], m.footers[m.cursor]
+	m.cursor = next
+	return m
+}
+
+func (m Model) remove() Model {
+	if len(m.footers) == 0 {
+		return m
+	}
+	remaining := append(append([]string{}, m.footers[:m.cursor]...), m.footers[m.cursor+1:]...)
+	if err := m.canRemove(remaining, m.footers[m.cursor]); err != nil {
+		m.err = err
+		return m
+	}
+	m.footers = remaining
+	if m.cursor >= len(m.footers) && m.cursor > 0 {
+		m.cursor--
+	}
+	return m
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.err = nil
+		switch {
+		case msg.Type == tea.KeyUp && m.cursor > 0:
+			m.cursor--
+		case msg.Type == tea.KeyDown && m.cursor < len(m.footers)-1:
+			m.cursor++
+		case msg.Type == tea.KeyShiftUp || msg.String() == "K":
+			m = m.move(-1)
+		case msg.Type == tea.KeyShiftDown || msg.String() == "J":
+			m = m.move(1)
+		case msg.Type == tea.KeyDelete || msg.Type == tea.KeyBackspace || msg.String() == "d":
+			m = m.remove()
+		}
+	case tea.WindowSizeMsg:
+		m.helpBar, _ = m.helpBar.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) View() string {
+	s := strings.Builder{}
+	s.WriteString(config.Faint(config.T(config.LabelFooters)) + "\n")
+	if len(m.footers) == 0 {
+		s.WriteString("   " + config.Faint(config.T(config.LabelNoFooters)) + "\n")
+	}
+	for i, footer := range m.footers {
+		// continuation lines of multi-line footers line up with the first
+		footer = strings.ReplaceAll(footer, "\n", "\n   ")
+		if i == m.cursor {
+			s.WriteString(" > " + config.Underline(footer) + "\n")
+		} else {
+			s.WriteString("   " + footer + "\n")
+		}
+	}
+	if m.err != nil {
+		s.WriteString("   " + config.Underline(m.err.Error()) + "\n")
+	}
+	s.WriteString("\n" + m.helpBar.View() + "\n")
+	return s.String()
+}
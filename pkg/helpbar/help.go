@@ -9,12 +9,17 @@ import (
 )
 
 type Model struct {
-	// each item should already be joined with an ":", e.g. "foo: bar"
+	// each item should be a message key or already be joined with an ":",
+	// e.g. "foo: bar"
 	items []string
 	width int
 }
 
-func NewModel(items ...string) Model {
+func NewModel(keys ...string) Model {
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = config.T(key)
+	}
 	return Model{items, 0}
 }
 
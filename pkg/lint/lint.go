@@ -0,0 +1,287 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/skalt/git-cc/pkg/config"
+	"github.com/skalt/git-cc/pkg/parser"
+)
+
+type Level int
+
+const (
+	Warning Level = iota
+	Error
+)
+
+func (l Level) String() string {
+	switch l {
+	case Warning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+type Violation struct {
+	Rule    string
+	Level   Level
+	Message string
+	Fix     string // a suggested fix, if any
+	// whether Strict promoted this violation from a warning to an error
+	Promoted bool
+}
+
+func (v Violation) String() string {
+	level := v.Level.String()
+	if v.Promoted {
+		level += " (strict; normally a warning)"
+	}
+	return fmt.Sprintf("%s: [%s] %s", level, v.Rule, v.Message)
+}
+
+// a Rule inspects a commit message and its parsed form, returning a
+// description of the problem and optionally a suggested fix, or "" if the
+// message complies.
+type Rule struct {
+	Name  string
+	Level Level
+	Check func(message string, cc *parser.CC, cfg config.Cfg) (problem string, fix string)
+}
+
+// returns the first line of a commit message
+func header(message string) string {
+	return strings.TrimRight(strings.SplitN(message, "\n", 2)[0], "\r")
+}
+
+// the names of each {name: description} option
+func names(options []map[string]string) []string {
+	result := []string{}
+	for _, option := range options {
+		for name := range option {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// a "did you mean ...?" suggestion for the closest options to `input`, if any
+// are close.
+func DidYouMean(input string, options []map[string]string) string {
+	suggestions := Suggest(input, names(options))
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("did you mean '%s'?", strings.Join(suggestions, "' or '"))
+}
+
+// the Rules, in order of importance.
+var Rules = []Rule{
+	{"header", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if cc.Type == "" {
+			return "missing a commit type", "start the message with `type: description`"
+		}
+		if cc.Description == "" {
+			return "missing a description", "add a description after `" + cc.Type + ": `"
+		}
+		return "", ""
+	}},
+	{"type-enum", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if cc.Type == "" || cc.ValidCommitType(cfg.CommitTypes) {
+			return "", ""
+		}
+		return config.Tf(config.ErrorUnknownType, cc.Type), DidYouMean(cc.Type, cfg.CommitTypes)
+	}},
+	{"scope-enum", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if cc.Scope == "" || cc.ValidScope(cfg.Scopes) {
+			return "", ""
+		}
+		return config.Tf(config.ErrorUnknownScope, cc.Scope), DidYouMean(cc.Scope, cfg.Scopes)
+	}},
+	{"scope-required", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if err := cfg.CheckScopeRequired(cc.Type, cc.Scope); err != nil {
+			return err.Error(), fmt.Sprintf("add a scope, e.g. '%s(scope): ...'", cc.Type)
+		}
+		return "", ""
+	}},
+	{"scope-type", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if err := cfg.CheckScopeAllowed(cc.Type, cc.Scope); err != nil {
+			return config.Tf(config.ErrorScopeNotAllowed, cc.Type, cc.Scope), "use one of: " + strings.Join(cfg.ScopesByType[cc.Type], ", ")
+		}
+		return "", ""
+	}},
+	{"footer-separation", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		_, err := parser.ParseStrictly(message, cfg.ParserOption())
+		if errors.Is(err, parser.ErrFooterNotSeparated) {
+			return err.Error(), "add a blank line before the footers"
+		}
+		return "", ""
+	}},
+	{"footer-required", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		missing := cfg.MissingFooters(cc.Type, cc.Footers)
+		if len(missing) == 0 {
+			return "", ""
+		}
+		return config.MissingFootersError(cc.Type, missing).Error(),
+			fmt.Sprintf("add `%s: ...` after a blank line", missing[0])
+	}},
+	{"breaking-change-description", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if !cfg.NeedsBreakingChangeDescription(cc.BreakingChange, cc.Footers) {
+			return "", ""
+		}
+		return "breaking change without a description",
+			"explain it in a `BREAKING CHANGE: ...` footer after a blank line"
+	}},
+	{"placeholder-description", Warning, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		placeholder := cfg.Placeholder(cc.Description)
+		if placeholder == "" {
+			return "", ""
+		}
+		return fmt.Sprintf("description starts with the placeholder '%s'", placeholder),
+			"describe what the commit changes"
+	}},
+	{"subject-mood", Warning, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		word, imperative := cfg.MoodSuggestion(cc.Description)
+		if word == "" {
+			return "", ""
+		}
+		return fmt.Sprintf("description starts with '%s' rather than the imperative", word),
+			fmt.Sprintf("use '%s'", imperative)
+	}},
+	{"type-case", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if err := cfg.CheckTypeCase(cc.Type); err != nil {
+			return fmt.Sprintf("type '%s' should be lower-case", cc.Type),
+				fmt.Sprintf("use '%s'", strings.ToLower(cc.Type))
+		}
+		return "", ""
+	}},
+	{"scope-case", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if expected := config.ApplyScopeCase(cfg.ScopeCase, cc.Scope); expected != cc.Scope {
+			return fmt.Sprintf("scope should be %s-case", cfg.ScopeCase),
+				fmt.Sprintf("use '%s'", expected)
+		}
+		return "", ""
+	}},
+	{"subject-case", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if config.MatchesCase(cfg.SubjectCase, cc.Description) {
+			return "", ""
+		}
+		return fmt.Sprintf("description should be %s-case", cfg.SubjectCase),
+			fmt.Sprintf("use '%s'", config.ApplyCase(cfg.SubjectCase, cc.Description))
+	}},
+	{"header-max-length", Warning, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		length := config.Width(header(message))
+		if length <= cfg.HeaderMaxLength {
+			return "", ""
+		}
+		return fmt.Sprintf(
+				"header is %d characters long; the limit is %d",
+				length, cfg.HeaderMaxLength,
+			), fmt.Sprintf(
+				"shorten the description by %d characters",
+				length-cfg.HeaderMaxLength,
+			)
+	}},
+	{"scope-max-length", Warning, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		if err := cfg.CheckScopeLength(cc.Scope); err != nil {
+			return err.Error(), fmt.Sprintf(
+				"shorten the scope by %d characters", len([]rune(cc.Scope))-cfg.ScopeMaxLength,
+			)
+		}
+		return "", ""
+	}},
+	{"body-max-length", Error, func(message string, cc *parser.CC, cfg config.Cfg) (string, string) {
+		length := len([]rune(cc.Body))
+		if cfg.BodyMaxLength == 0 || length <= cfg.BodyMaxLength {
+			return "", ""
+		}
+		return fmt.Sprintf(
+				"body is %d characters long; the limit is %d",
+				length, cfg.BodyMaxLength,
+			), fmt.Sprintf(
+				"shorten the body by %d characters",
+				length-cfg.BodyMaxLength,
+			)
+	}},
+}
+
+// check a commit message against each of the Rules.
+func Lint(message string, cfg config.Cfg) []Violation {
+	cc, _ := parser.ParseAsMuchOfCCAsPossible(message, cfg.ParserOption())
+	if cfg.ScopePosition == config.ScopeSuffix && cc.Scope == "" {
+		cc.Description, cc.Scope = parser.SplitScopeSuffix(cc.Description)
+	}
+	violations := []Violation{}
+	for _, rule := range Rules {
+		level := rule.Level
+		if (rule.Name == "header-max-length" || rule.Name == "scope-max-length") && cfg.EnforceMaxLength {
+			level = Error
+		}
+		if rule.Name == "placeholder-description" && cfg.BlockPlaceholderDescriptions {
+			level = Error
+		}
+		if problem, fix := rule.Check(message, cc, cfg); problem != "" {
+			violations = append(violations, Violation{Rule: rule.Name, Level: level, Message: problem, Fix: fix})
+		}
+	}
+	return violations
+}
+
+// whether any of the violations should fail the lint.
+func Failed(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Level == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// promote each warning to an error so that it fails the lint, e.g. to gate CI
+// more strictly.
+func Strict(violations []Violation) []Violation {
+	result := make([]Violation, len(violations))
+	for i, v := range violations {
+		if v.Level == Warning {
+			v.Level, v.Promoted = Error, true
+		}
+		result[i] = v
+	}
+	return result
+}
+
+// the most important violation: the first error, or else the first warning.
+func headline(violations []Violation) (Violation, bool) {
+	for _, v := range violations {
+		if v.Level == Error {
+			return v, true
+		}
+	}
+	if len(violations) > 0 {
+		return violations[0], true
+	}
+	return Violation{}, false
+}
+
+// print a one-line summary of the most important violation followed by the
+// details of each violation and any suggested fixes.
+func Report(w io.Writer, violations []Violation) {
+	first, ok := headline(violations)
+	if !ok {
+		return
+	}
+	summary := first.Message
+	if first.Fix != "" {
+		summary += " -- " + first.Fix
+	}
+	fmt.Fprintf(w, "%s\n\n", summary)
+	for _, v := range violations {
+		fmt.Fprintln(w, v.String())
+		if v.Fix != "" {
+			fmt.Fprintf(w, "  fix: %s\n", v.Fix)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+func TestStrict(t *testing.T) {
+	cfg := config.Cfg{CommitTypes: []map[string]string{{"feat": ""}}, HeaderMaxLength: 10}
+	violations := Lint("feat: a long enough description\n", cfg)
+	if Failed(violations) {
+		t.Fatalf("expected only warnings, got %v", violations)
+	}
+	strict := Strict(violations)
+	if !Failed(strict) {
+		t.Errorf("expected warnings to fail under Strict, got %v", strict)
+	}
+	if !strings.HasPrefix(strict[0].String(), "error (strict; normally a warning): [header-max-length]") {
+		t.Errorf("expected the promotion to be noted, got %q", strict[0].String())
+	}
+	if violations[0].Level != Warning {
+		t.Error("expected Strict to leave the original violations alone")
+	}
+}
+
+func TestRequiredFooters(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}, {"fix": ""}},
+		HeaderMaxLength: 72,
+		RequiredFooters: map[string][]string{"fix": {"Refs", "Reviewed-by"}},
+	}
+	test := func(message string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			violations := Lint(message, cfg)
+			actual := ""
+			if len(violations) > 0 {
+				actual = violations[0].Message
+			}
+			if actual != expected {
+				t.Errorf("expected %q, got %v", expected, violations)
+			}
+		}
+	}
+	t.Run("feat", test("feat: x\n", ""))
+	t.Run("fix without footers", test("fix: x\n", "'fix' commits need a Refs and a Reviewed-by footer"))
+	t.Run("fix with one", test("fix: x\n\nRefs: #1\n", "'fix' commits need a Reviewed-by footer"))
+	t.Run("fix with both", test("fix: x\n\nRefs: #1\nReviewed-by: Z\n", ""))
+}
+
+func TestRequiredScopes(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}, {"fix": ""}},
+		Scopes:          []map[string]string{{"cli": ""}, {"ui": ""}},
+		HeaderMaxLength: 72,
+		RequireScopeFor: []string{"fix"},
+		ScopesByType:    map[string][]string{"fix": {"cli"}, "feat": {"ui"}},
+	}
+	for message, expected := range map[string]string{
+		"feat: x\n":      "",
+		"fix(cli): x\n":  "",
+		"fix: x\n":       "'fix' commits need a scope",
+		"fix!: x\n":      "'fix' commits need a scope",
+		"feat(ui): x\n":  "",
+		"feat(cli): x\n": "'feat' commits can't have the scope 'cli'",
+	} {
+		violations := Lint(message, cfg)
+		actual := ""
+		if len(violations) > 0 {
+			actual = violations[0].Message
+		}
+		if actual != expected {
+			t.Errorf("%q: expected %q, got %v", message, expected, violations)
+		}
+	}
+}
+
+func TestRequiredBreakingChangeDescriptions(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:                      []map[string]string{{"feat": ""}},
+		HeaderMaxLength:                  72,
+		RequireBreakingChangeDescription: true,
+	}
+	test := func(message string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			actual := ""
+			if violations := Lint(message, cfg); len(violations) > 0 {
+				actual = violations[0].Rule
+			}
+			if actual != expected {
+				t.Errorf("expected %q, got %q", expected, actual)
+			}
+		}
+	}
+	t.Run("not breaking", test("feat: x\n", ""))
+	t.Run("bang without a description", test("feat!: x\n", "breaking-change-description"))
+	t.Run("bang with a description", test("feat!: x\n\nBREAKING CHANGE: drops y\n", ""))
+	t.Run("footer only", test("feat: x\n\nBREAKING-CHANGE: drops y\n", ""))
+}
+
+func TestBodyMaxLength(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}},
+		HeaderMaxLength: 72,
+		BodyMaxLength:   10,
+	}
+	message := "feat: add a thing\n\nthis body is far too long\n\nRefs: #123456789\n"
+	violations := Lint(message, cfg)
+	if len(violations) != 1 || violations[0].Rule != "body-max-length" {
+		t.Fatalf("expected only a body-max-length violation, got %v", violations)
+	}
+	cfg.BodyMaxLength = 0
+	if violations := Lint(message, cfg); len(violations) != 0 {
+		t.Errorf("0 should mean unlimited, got %v", violations)
+	}
+}
+
+func TestPlaceholderDescriptions(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:             []map[string]string{{"feat": ""}},
+		HeaderMaxLength:         72,
+		PlaceholderDescriptions: config.DefaultPlaceholderDescriptions,
+	}
+	violations := Lint("feat: wip\n", cfg)
+	if len(violations) != 1 || violations[0].Rule != "placeholder-description" || Failed(violations) {
+		t.Errorf("expected a placeholder warning, got %v", violations)
+	}
+	if violations := Lint("feat: wipe the cache\n", cfg); len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+	cfg.BlockPlaceholderDescriptions = true
+	if violations := Lint("feat: Tmp: parser\n", cfg); !Failed(violations) {
+		t.Errorf("expected blocked placeholders to fail, got %v", violations)
+	}
+}
+
+func TestTypeCase(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}, {"Docs": ""}},
+		HeaderMaxLength: 72,
+	}
+	if violations := Lint("Docs: x\n", cfg); len(violations) != 0 {
+		t.Errorf("expected mixed-case types to pass by default, got %v", violations)
+	}
+	cfg.TypeCase = config.CaseLower
+	violations := Lint("Docs: x\n", cfg)
+	if len(violations) != 1 || violations[0].Rule != "type-case" || violations[0].Fix != "use 'docs'" {
+		t.Errorf("expected a type-case violation, got %v", violations)
+	}
+}
+
+func TestSubjectMood(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}},
+		HeaderMaxLength: 72,
+		SubjectMood:     config.MoodImperative,
+	}
+	violations := Lint("feat: Added a flag\n", cfg)
+	if len(violations) != 1 || violations[0].Rule != "subject-mood" || Failed(violations) {
+		t.Errorf("expected a mood warning, got %v", violations)
+	} else if violations[0].Fix != "use 'add'" {
+		t.Errorf("expected a suggestion to use 'add', got %q", violations[0].Fix)
+	}
+	if violations := Lint("feat: add a flag\n", cfg); len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+}
+
+func TestScopeMaxLength(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}},
+		Scopes:          []map[string]string{{"parse": ""}, {"parser": ""}},
+		HeaderMaxLength: 72,
+		ScopeMaxLength:  5,
+	}
+	if violations := Lint("feat(parse): x\n", cfg); len(violations) != 0 {
+		t.Errorf("expected a scope at the limit to pass, got %v", violations)
+	}
+	violations := Lint("feat(parser): x\n", cfg)
+	if len(violations) != 1 || violations[0].Rule != "scope-max-length" || Failed(violations) {
+		t.Errorf("expected a warning one character over the limit, got %v", violations)
+	}
+	cfg.EnforceMaxLength = true
+	if violations := Lint("feat(parser): x\n", cfg); !Failed(violations) {
+		t.Errorf("expected an enforced limit to fail, got %v", violations)
+	}
+}
+
+func TestMeasuringHeadersInColumns(t *testing.T) {
+	cfg := config.Cfg{CommitTypes: []map[string]string{{"feat": ""}}, HeaderMaxLength: 12}
+	// 10 runes, but 14 columns
+	if violations := Lint("feat: 添加预览\n", cfg); len(violations) != 1 || violations[0].Rule != "header-max-length" {
+		t.Errorf("expected the wide header to be too long, got %v", violations)
+	}
+	if violations := Lint("feat: 添加预\n", cfg); len(violations) != 0 {
+		t.Errorf("expected a header of 12 columns to fit, got %v", violations)
+	}
+}
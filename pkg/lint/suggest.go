@@ -0,0 +1,72 @@
+package lint
+
+import "sort"
+
+// the number of single-rune insertions, deletions, or substitutions needed to
+// turn `a` into `b`.
+func Levenshtein(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+func min(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+	return first
+}
+
+// the most suggestions Suggest returns
+const maxSuggestions = 3
+
+// the options close enough to `input` to be what the user meant, closest
+// first. Only options within a third of the input's length (at least one edit)
+// are considered close.
+func Suggest(input string, options []string) []string {
+	maxDistance := len([]rune(input)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	type candidate struct {
+		option   string
+		distance int
+	}
+	candidates := []candidate{}
+	for _, option := range options {
+		if option == "" || option == input {
+			continue
+		}
+		if d := Levenshtein(input, option); d <= maxDistance {
+			candidates = append(candidates, candidate{option, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].option < candidates[j].option
+	})
+	result := []string{}
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		result = append(result, candidates[i].option)
+	}
+	return result
+}
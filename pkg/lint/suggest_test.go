@@ -0,0 +1,42 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skalt/git-cc/pkg/config"
+)
+
+func TestSuggest(t *testing.T) {
+	options := []string{"feat", "fix", "docs", "refactor"}
+	t.Run("near misses are suggested", func(t *testing.T) {
+		got := Suggest("fet", options)
+		if len(got) == 0 || got[0] != "feat" {
+			t.Errorf("expected feat first, got %v", got)
+		}
+	})
+	t.Run("distant inputs aren't", func(t *testing.T) {
+		if got := Suggest("xyzzy", options); len(got) != 0 {
+			t.Errorf("expected no suggestions, got %v", got)
+		}
+	})
+}
+
+func TestReport(t *testing.T) {
+	cfg := config.Cfg{
+		CommitTypes:     []map[string]string{{"feat": ""}, {"fix": ""}},
+		Scopes:          []map[string]string{{"parser": ""}},
+		HeaderMaxLength: 10,
+	}
+	violations := Lint("fet(parser): a long enough description\n", cfg)
+	out := bytes.Buffer{}
+	Report(&out, violations)
+	headline := strings.SplitN(out.String(), "\n", 2)[0]
+	if headline != "unknown type 'fet' -- did you mean 'feat'?" {
+		t.Errorf("unexpected headline %q", headline)
+	}
+	if !strings.Contains(out.String(), "[header-max-length]") {
+		t.Errorf("expected details of every violation, got:\n%s", out.String())
+	}
+}
@@ -2,7 +2,9 @@ package parser
 
 import (
 	"fmt"
+	"io"
 	"regexp"
+	"unicode/utf8"
 )
 
 // see https://medium.com/@armin.heller/using-parser-combinators-in-go-e63b3ad69c94,
@@ -33,7 +35,7 @@ func LastRuneOf(parser Parser) Parser {
 		if maxIndex < 0 {
 			return parser([]rune{})
 		} else {
-			lastRune := input[maxIndex-1:]
+			lastRune := input[maxIndex:]
 			return parser(lastRune)
 		}
 	}
@@ -61,12 +63,39 @@ func TakeUntil(parser Parser) Parser {
 	}
 }
 
-func Marked(mark string) func(Parser) Parser {
-	if len(mark) == 0 {
-		panic("empty mark")
+// like TakeUntil, but only tries `parser` at the start of the input and of
+// each line, e.g. for footers. This keeps long lines linear-time.
+func TakeUntilLineStart(parser Parser) Parser {
+	return func(input []rune) (*Result, error) {
+		for i := range input {
+			if i > 0 && input[i-1] != '\n' {
+				continue
+			}
+			if _, err := parser(input[i:]); err == nil {
+				return &Result{
+					Value:     string(input[:i]),
+					Remaining: input[i:],
+				}, nil
+			}
+		}
+		if _, err := parser([]rune{}); err == nil {
+			return &Result{
+				Value:     string(input),
+				Remaining: []rune{},
+			}, nil
+		}
+		return nil, fmt.Errorf("didn't match parser")
 	}
+}
+
+// types the results of a parser as `mark`. An empty mark is an error, since
+// untyped results can't be told apart from unmarked ones.
+func Marked(mark string) func(Parser) Parser {
 	return func(parser Parser) Parser {
 		return func(input []rune) (*Result, error) {
+			if len(mark) == 0 {
+				return nil, fmt.Errorf("empty mark")
+			}
 			result, err := parser(input)
 			if err != nil {
 				return nil, err
@@ -116,22 +145,6 @@ func Not(parser Parser) Parser {
 	}
 }
 
-func toRunes(i interface{}) []rune {
-	switch i.(type) {
-	case string:
-		str, _ := i.(string)
-		return []rune(str)
-	case rune:
-		r, _ := i.(rune)
-		return []rune{r}
-	case []rune:
-		runes, _ := i.([]rune)
-		return runes
-	default:
-		panic(fmt.Errorf("%v is not string or []rune", i))
-	}
-}
-
 func Tag(tag string) Parser {
 	toMatch := []rune(tag)
 	return func(input []rune) (*Result, error) {
@@ -169,9 +182,9 @@ func Any(parsers ...Parser) Parser {
 
 func Some(parsers ...Parser) Parser {
 	return func(input []rune) (*Result, error) {
-		var currentInput = make([]rune, len(input))
+		// parsers never modify their input, so it needn't be copied
+		currentInput := input
 		children := make([]Result, len(parsers))
-		copy(currentInput, input)
 		var err error
 		var result *Result
 		for i, parser := range parsers {
@@ -199,7 +212,7 @@ func Some(parsers ...Parser) Parser {
 
 func Empty(input []rune) (*Result, error) {
 	if len(input) == 0 {
-		return nil, nil
+		return &Result{Remaining: input}, nil
 	} else {
 		return nil, fmt.Errorf("Not the end")
 	}
@@ -211,7 +224,7 @@ func OneOfTheseRunes(str string) Parser {
 	for _, char := range []rune(str) {
 		set[char] = present
 	}
-	parsers := make([]Parser, len(set))
+	parsers := make([]Parser, 0, len(set))
 	for char := range set {
 		parsers = append(parsers, LiteralRune(char))
 	}
@@ -280,23 +293,44 @@ func Many1(parser Parser) Parser {
 	}
 }
 
+// reads runes from a slice, so regexes only consume as much input as they
+// need rather than the whole remaining message.
+type runeReader struct {
+	runes []rune
+	read  int
+}
+
+func (r *runeReader) ReadRune() (rune, int, error) {
+	if r.read >= len(r.runes) {
+		return 0, 0, io.EOF
+	}
+	next := r.runes[r.read]
+	r.read++
+	return next, utf8.RuneLen(next), nil
+}
+
+// matches `pattern` at the start of the input. An invalid pattern fails to
+// match anything, returning the compilation error.
 func Regex(pattern string) Parser {
-	re := regexp.MustCompile(`^` + pattern) // should be from the start of the bytes
+	re, err := regexp.Compile(`^` + pattern) // should be from the start of the bytes
 	return func(input []rune) (*Result, error) {
-		b := []byte(string(input))
-		result := re.FindIndex(b) //Match(b)
-		if result == nil {        // no match found
+		if err != nil {
+			return nil, err
+		}
+		result := re.FindReaderIndex(&runeReader{runes: input})
+		if result == nil { // no match found
 			return nil, fmt.Errorf("no match for /%s/", pattern)
-		} else {
-			// a rune can be multiple bytes, so convert the reult back to runes
-			startByte := result[0]
-			endByte := result[1]
-			endRune := len([]rune(string(b[startByte:endByte])))
-			return &Result{
-				Value:     string(input[:endRune]),
-				Remaining: input[endRune:],
-			}, nil
 		}
+		// a rune can be multiple bytes, so convert the result back to runes
+		endRune, bytes := 0, 0
+		for bytes < result[1] {
+			bytes += utf8.RuneLen(input[endRune])
+			endRune++
+		}
+		return &Result{
+			Value:     string(input[:endRune]),
+			Remaining: input[endRune:],
+		}, nil
 	}
 }
 
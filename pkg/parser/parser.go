@@ -1,17 +1,23 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
 type CC struct {
-	Type           string
-	Scope          string
-	Description    string
-	Body           string
-	Footers        []string
-	BreakingChange bool
+	Type        string
+	Scope       string
+	Description string
+	Body        string
+	Footers     []string
+	// text after a blank line following the last footer, e.g. a signature
+	// block, kept verbatim since it isn't a footer
+	Trailing       string
+	BreakingChange bool // from either a `!` or a BREAKING CHANGE footer
+	Bang           bool // whether the header has a `!`
 }
 
 func trimWhitespace(s string) string {
@@ -26,6 +32,7 @@ func (cc *CC) Ingest(r Result) *CC {
 		cc.Scope = r.Value
 	case "BreakingChangeBang":
 		cc.BreakingChange = true
+		cc.Bang = true
 	case "Description":
 		cc.Description = trimWhitespace(r.Value)
 	case "Body":
@@ -40,21 +47,39 @@ func (cc *CC) Ingest(r Result) *CC {
 			}
 			footers = append(footers, trimWhitespace(footer.Value))
 		}
-		cc.Footers = footers
+		cc.Footers, cc.Trailing = splitTrailing(footers)
 	}
 	return cc
 }
 
-func (cc *CC) ToString() string {
+var blankLine = regexp.MustCompile(`\r?\n[ \t]*\r?\n`)
+
+// split any text after a blank line in the last of `footers` from it: like
+// git's trailers, footers end at a blank line. BREAKING CHANGE footers are
+// left whole, since their explanations may run to several paragraphs.
+func splitTrailing(footers []string) ([]string, string) {
+	last := len(footers) - 1
+	if last < 0 || IsBreakingChangeFooter(footers[last]) {
+		return footers, ""
+	}
+	parts := blankLine.Split(footers[last], 2)
+	if len(parts) < 2 {
+		return footers, ""
+	}
+	footers[last] = trimWhitespace(parts[0])
+	return footers, trimWhitespace(parts[1])
+}
+
+func (cc *CC) ToString(opts ...Option) string {
 	s := strings.Builder{}
 	s.WriteString(cc.Type)
 	if cc.Scope != "" {
 		s.WriteString(fmt.Sprintf("(%s)", cc.Scope))
 	}
-	if cc.BreakingChange {
+	if cc.Bang || (cc.BreakingChange && !cc.HasBreakingChangeFooter()) {
 		s.WriteString("!")
 	}
-	s.WriteString(": ")
+	s.WriteString(options(opts).separator)
 	s.WriteString(cc.Description)
 	s.WriteString("\n\n")
 	body := trimWhitespace(cc.Body)
@@ -65,9 +90,22 @@ func (cc *CC) ToString() string {
 	for _, footer := range cc.Footers {
 		s.WriteString(trimWhitespace(footer) + "\n")
 	}
+	if cc.Trailing != "" {
+		s.WriteString("\n" + cc.Trailing + "\n")
+	}
 	return s.String()
 }
 
+// whether any footer is a BREAKING CHANGE footer
+func (cc *CC) HasBreakingChangeFooter() bool {
+	for _, footer := range cc.Footers {
+		if IsBreakingChangeFooter(footer) {
+			return true
+		}
+	}
+	return false
+}
+
 func (cc *CC) MinimallyValid() bool {
 	return cc.Type != "" && cc.Description != ""
 }
@@ -99,6 +137,42 @@ var Newline = Marked("Newline")(Any(LiteralRune('\n'), Tag("\r\n")))
 var DoubleNewline = Sequence(Newline, Newline)
 var ColonSep = Tag(": ")
 
+// what separates a header's type, scope, and `!` from its description, as the
+// spec requires
+const StandardHeaderSeparator = ": "
+
+// changes how messages are parsed and formatted; without any, they follow the
+// spec.
+type Option func(*settings)
+
+type settings struct {
+	separator string
+}
+
+// use `separator` rather than the StandardHeaderSeparator, or the standard
+// one if `separator` is empty. Other separators only suit tools migrating from
+// legacy formats, since other parsers won't read the headers they produce.
+func WithHeaderSeparator(separator string) Option {
+	return func(s *settings) {
+		if separator != "" {
+			s.separator = separator
+		}
+	}
+}
+
+func options(opts []Option) settings {
+	s := settings{separator: StandardHeaderSeparator}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// matches the header `separator`
+func HeaderSep(separator string) Parser {
+	return Tag(separator)
+}
+
 // The key words “MUST”, “MUST NOT”, “REQUIRED”, “SHALL”, “SHALL NOT”, “SHOULD”, “SHOULD NOT”, “RECOMMENDED”, “MAY”, and “OPTIONAL” in this document are to be interpreted as described in RFC 2119.
 
 // Commits MUST be prefixed with a type, which consists of a noun, feat, fix, etc., followed by the OPTIONAL scope, OPTIONAL !, and REQUIRED terminal colon and space.
@@ -126,24 +200,285 @@ var FooterToken = Any(
 	Sequence(KebabWord, Any(ColonSep, Tag(" #"))),
 )
 
-var Body = Marked("Body")(TakeUntil(Any(Empty, FooterToken)))
+var Body = Marked("Body")(TakeUntilLineStart(Any(Empty, FooterToken)))
 var Footer = Marked("Footer")(
-	Sequence(FooterToken, TakeUntil(Any(Empty, FooterToken))),
+	Sequence(FooterToken, TakeUntilLineStart(Any(Empty, FooterToken))),
 )
 var Footers = Marked("Footers")(Many0(Footer))
 
-func ParseAsMuchOfCCAsPossible(fullCommit string) (*CC, error) {
-	parsed, err := Some(
-		CommitType, Opt(Scope), Opt(BreakingChangeBang), ColonSep, ShortDescription,
+// returns the token of a footer like `Refs: #1` or `Refs #1`, or "" if
+// `footer` isn't a footer.
+func FooterTokenOf(footer string) string {
+	result, err := FooterToken([]rune(footer))
+	if err != nil || len(result.Children) == 0 {
+		return ""
+	}
+	return result.Children[0].Value
+}
+
+// trim extra whitespace from a footer's value: whitespace around the value and
+// at the end of each of its lines. The token and separator are kept as-is.
+func TrimFooterValue(footer string) string {
+	result, err := FooterToken([]rune(footer))
+	if err != nil {
+		return trimWhitespace(footer)
+	}
+	prefix := footer[:len(footer)-len(string(result.Remaining))]
+	lines := strings.Split(trimWhitespace(string(result.Remaining)), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r\t ")
+	}
+	return prefix + strings.Join(lines, "\n")
+}
+
+// whether `footer` is a `BREAKING CHANGE: ...` or `BREAKING-CHANGE: ...` footer
+func IsBreakingChangeFooter(footer string) bool {
+	_, err := Sequence(BreakingChange, ColonSep)([]rune(footer))
+	return err == nil
+}
+
+// append each of the `extra` footers whose token isn't already present in
+// `footers`, e.g. to avoid re-adding a trailer when re-editing a commit.
+func MergeFooters(footers []string, extra []string) []string {
+	result := append([]string{}, footers...)
+	present := map[string]bool{}
+	for _, footer := range footers {
+		present[strings.ToLower(FooterTokenOf(footer))] = true
+	}
+	for _, footer := range extra {
+		token := strings.ToLower(FooterTokenOf(footer))
+		if !present[token] {
+			result = append(result, footer)
+			present[token] = true
+		}
+	}
+	return result
+}
+
+var scopeSuffix = regexp.MustCompile(`^(.*\S) \(([^()\s]+)\)$`)
+
+// split a description written in the scope-last layout, e.g. `add x (cli)`,
+// into its description and scope. Returns the description unchanged and an
+// empty scope if there's no trailing scope.
+func SplitScopeSuffix(description string) (string, string) {
+	match := scopeSuffix.FindStringSubmatch(description)
+	if match == nil {
+		return description, ""
+	}
+	return match[1], match[2]
+}
+
+// the inverse of SplitScopeSuffix
+func JoinScopeSuffix(description string, scope string) string {
+	if scope == "" {
+		return description
+	}
+	return fmt.Sprintf("%s (%s)", description, scope)
+}
+
+// returned by SplitCommit when the header isn't followed by a blank line.
+var ErrBodyNotSeparated = errors.New(
+	"the header must be separated from the body by a blank line",
+)
+
+// Split a raw commit message into its header, body, and footers without
+// otherwise interpreting or reformatting them:
+//
+//   - the header is the first line.
+//   - the footers start at the first line after a blank line that begins with
+//     a footer token like `Refs: ` or `Refs #`, and run to the end.
+//   - the body is everything between the header and the footers.
+//
+// Blank lines around each section are dropped; the text within is unchanged.
+// Returns ErrBodyNotSeparated, along with the sections, if the line after the
+// header isn't blank.
+func SplitCommit(full string) (header string, body string, footers string, err error) {
+	lines := strings.Split(full, "\n")
+	isBlank := func(line string) bool { return strings.TrimSpace(line) == "" }
+	header = strings.TrimRight(lines[0], "\r")
+	if header == "" {
+		return "", "", "", errors.New("missing a header")
+	}
+	rest := lines[1:]
+	if len(rest) > 0 && !isBlank(rest[0]) {
+		err = ErrBodyNotSeparated
+	}
+	footerStart := len(rest)
+	for i, line := range rest {
+		if i > 0 && isBlank(rest[i-1]) && FooterTokenOf(line) != "" {
+			footerStart = i
+			break
+		}
+	}
+	trim := func(section []string) string {
+		for len(section) > 0 && isBlank(section[0]) {
+			section = section[1:]
+		}
+		for len(section) > 0 && isBlank(section[len(section)-1]) {
+			section = section[:len(section)-1]
+		}
+		return strings.Join(section, "\n")
+	}
+	return header, trim(rest[:footerStart]), trim(rest[footerStart:]), err
+}
+
+// returned by ParseStrictly when footers aren't preceded by a blank line.
+var ErrFooterNotSeparated = errors.New(
+	"footers must be separated from the header and body by a blank line",
+)
+
+var endsWithBlankLine = regexp.MustCompile(`\r?\n\r?\n$`)
+
+func parse(fullCommit string, opts []Option) (*Result, error) {
+	return Some(
+		CommitType, Opt(Scope), Opt(BreakingChangeBang), HeaderSep(options(opts).separator), ShortDescription,
 		Opt(Newline), Opt(Newline),
 		Opt(Body),
 		Opt(Footers),
 	)([]rune(fullCommit))
+}
+
+func ingestAll(parsed *Result) *CC {
 	result := &CC{}
 	if parsed != nil && parsed.Children != nil {
 		for _, token := range parsed.Children {
 			result = result.Ingest(token)
 		}
 	}
+	return result
+}
+
+// Parse only a conventional commit header, e.g. `type(scope)!: description`.
+func ParseHeader(header string, opts ...Option) (*CC, error) {
+	parsed, err := Some(
+		CommitType, Opt(Scope), Opt(BreakingChangeBang), HeaderSep(options(opts).separator), ShortDescription,
+	)([]rune(header))
+	if err == nil && len(parsed.Remaining) > 0 {
+		err = fmt.Errorf("unexpected input after header: %q", string(parsed.Remaining))
+	}
+	result := ingestAll(parsed)
+	if err == nil && strings.ContainsAny(header, "\r\n") {
+		err = fmt.Errorf("a header must be a single line: %q", header)
+	}
+	if err == nil && result.Type == "" {
+		err = fmt.Errorf("missing a commit type: %q", header)
+	}
 	return result, err
 }
+
+// whether `s` is a single word such as a commit type, e.g. `feat` or `feat-x`
+func IsSingleToken(s string) bool {
+	result, err := KebabWord([]rune(s))
+	return err == nil && len(result.Remaining) == 0
+}
+
+// returned by Parse to locate where parsing stopped. Line and Column are
+// 1-based; Offset counts runes from the start of the message.
+type ParseError struct {
+	Offset int
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// locate the rune `offset` in `message`
+func newParseError(message []rune, offset int, err error) *ParseError {
+	line, column := 1, 1
+	for _, r := range message[:offset] {
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	return &ParseError{Offset: offset, Line: line, Column: column, Err: err}
+}
+
+// Parse a full commit message leniently, like ParseAsMuchOfCCAsPossible.
+// This is the recommended entry point for programs embedding the parser: it
+// never panics. On error, the result holds whatever was parsed before the
+// error, e.g. only the Type of `feat:` with no description, and is empty if
+// nothing could be parsed. Parse errors are *ParseErrors.
+func Parse(fullCommit string, opts ...Option) (CC, error) {
+	parsed, err := parse(fullCommit, opts)
+	if err != nil {
+		input, offset := []rune(fullCommit), 0
+		if parsed != nil {
+			offset = len(input) - len(parsed.Remaining)
+		}
+		err = newParseError(input, offset, err)
+	}
+	return *ingestAll(parsed), err
+}
+
+// Leniently parse a commit: footers may directly follow the body. Prefer Parse.
+func ParseAsMuchOfCCAsPossible(fullCommit string, opts ...Option) (*CC, error) {
+	parsed, err := parse(fullCommit, opts)
+	return ingestAll(parsed), err
+}
+
+// Like ParseAsMuchOfCCAsPossible, but splits the body from the footers at the
+// first line after the header that's just `marker`, e.g. "---", rather than at
+// the first footer token. Footers parses only the text after the marker, so
+// lines that look like footers before it stay in the body, and text after it
+// that isn't a footer is appended to the body. Without such a line, or with an
+// empty `marker`, this is ParseAsMuchOfCCAsPossible.
+func ParseWithFooterMarker(fullCommit string, marker string, opts ...Option) (*CC, error) {
+	lines := strings.Split(fullCommit, "\n")
+	markerLine := -1
+	for i := 1; i < len(lines) && marker != ""; i++ {
+		if strings.TrimSpace(lines[i]) == marker {
+			markerLine = i
+			break
+		}
+	}
+	if markerLine < 0 {
+		return ParseAsMuchOfCCAsPossible(fullCommit, opts...)
+	}
+	result, err := ParseHeader(strings.TrimRight(lines[0], "\r"), opts...)
+	rest := trimWhitespace(strings.Join(lines[markerLine+1:], "\n"))
+	// neither fails, since each parser is optional
+	leading, _ := Opt(Body)([]rune(rest))
+	footers, _ := Footers(leading.Remaining)
+	result.Ingest(*footers)
+	body := []string{}
+	for _, part := range []string{
+		strings.Join(lines[1:markerLine], "\n"), leading.Value, string(footers.Remaining),
+	} {
+		if part = trimWhitespace(part); part != "" {
+			body = append(body, part)
+		}
+	}
+	result.Body = strings.Join(body, "\n\n")
+	return result, err
+}
+
+// Like ParseAsMuchOfCCAsPossible, but returns ErrFooterNotSeparated if the
+// footers aren't separated from the header or body by a blank line, as the
+// conventional commits spec requires.
+func ParseStrictly(fullCommit string, opts ...Option) (*CC, error) {
+	parsed, err := parse(fullCommit, opts)
+	result := ingestAll(parsed)
+	if err != nil || parsed == nil {
+		return result, err
+	}
+	preceding := strings.Builder{}
+	for _, child := range parsed.Children {
+		if child.Type == "Footers" && len(child.Children) > 0 {
+			if !endsWithBlankLine.MatchString(preceding.String()) {
+				return result, ErrFooterNotSeparated
+			}
+			break
+		}
+		preceding.WriteString(child.Value)
+	}
+	return result, nil
+}
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -282,3 +284,369 @@ func TestParsingPartialCommit(t *testing.T) {
 	t.Run("", test("feat:", CC{Type: "feat"}))
 	t.Run("", test("feat: ", CC{Type: "feat"}))
 }
+
+func TestParsingStrictly(t *testing.T) {
+	test := func(fullCommit string, expected error) func(*testing.T) {
+		return func(t *testing.T) {
+			_, err := ParseStrictly(fullCommit)
+			if err != expected {
+				fmt.Printf("expected error %+v, got %+v\n", expected, err)
+				t.Fail()
+			}
+			_, err = ParseAsMuchOfCCAsPossible(fullCommit)
+			if err != nil {
+				fmt.Printf("lenient parsing should accept %q: %+v\n", fullCommit, err)
+				t.Fail()
+			}
+		}
+	}
+	t.Run("accepts footers separated from the body", test(validCCWithFooters, nil))
+	t.Run("accepts footers separated from the header", test(validCCreversion, nil))
+	t.Run("accepts commits without footers", test(validCCWithScope, nil))
+	t.Run(
+		"accepts CRLF-separated footers",
+		test("fix: x\r\n\r\nbody\r\n\r\nRefs: #1", nil),
+	)
+	t.Run(
+		"rejects footers jammed against the body",
+		test("fix: x\n\nsome body\nRefs: #1", ErrFooterNotSeparated),
+	)
+	t.Run(
+		"rejects footers jammed against the header",
+		test("fix: x\nRefs: #1", ErrFooterNotSeparated),
+	)
+}
+
+func TestParsingHeader(t *testing.T) {
+	t.Run("parses a full header", func(t *testing.T) {
+		cc, err := ParseHeader("feat(lang)!: add polish language")
+		if err != nil || cc.Type != "feat" || cc.Scope != "lang" || !cc.BreakingChange {
+			fmt.Printf("unexpected result %+v, %+v\n", cc, err)
+			t.Fail()
+		}
+	})
+	t.Run("rejects trailing lines", func(t *testing.T) {
+		_, err := ParseHeader("feat: add polish language\n\nbody")
+		if err == nil {
+			t.Fail()
+		}
+	})
+	t.Run("parses a context without a description", func(t *testing.T) {
+		cc, err := ParseHeader("feat fix: ")
+		if err != nil || cc.Type != "feat fix" || IsSingleToken(cc.Type) {
+			fmt.Printf("unexpected result %+v, %+v\n", cc, err)
+			t.Fail()
+		}
+	})
+}
+
+func TestMergingFooters(t *testing.T) {
+	test := func(footers []string, extra []string, expected []string) func(*testing.T) {
+		return func(t *testing.T) {
+			actual := MergeFooters(footers, extra)
+			if fmt.Sprint(actual) != fmt.Sprint(expected) {
+				fmt.Printf("expected %q, got %q\n", expected, actual)
+				t.Fail()
+			}
+		}
+	}
+	t.Run("appends after existing footers", test(
+		[]string{"Refs: #1"}, []string{"Change-type: patch"},
+		[]string{"Refs: #1", "Change-type: patch"},
+	))
+	t.Run("doesn't duplicate present tokens", test(
+		[]string{"Change-type: minor", "Refs #1"}, []string{"Change-type: patch", "Refs: #2"},
+		[]string{"Change-type: minor", "Refs #1"},
+	))
+	t.Run("recognizes breaking changes", test(
+		[]string{"BREAKING CHANGE: x"}, []string{"BREAKING CHANGE: y"},
+		[]string{"BREAKING CHANGE: x"},
+	))
+	if token := FooterTokenOf("not a footer"); token != "" {
+		fmt.Printf("unexpected token %q\n", token)
+		t.Fail()
+	}
+}
+
+func TestSplittingScopeSuffixes(t *testing.T) {
+	test := func(input string, description string, scope string) func(*testing.T) {
+		return func(t *testing.T) {
+			actualDescription, actualScope := SplitScopeSuffix(input)
+			if actualDescription != description || actualScope != scope {
+				fmt.Printf("expected (%q, %q), got (%q, %q)\n", description, scope, actualDescription, actualScope)
+				t.Fail()
+			}
+			if scope != "" && JoinScopeSuffix(description, scope) != input {
+				fmt.Printf("%q doesn't round-trip\n", input)
+				t.Fail()
+			}
+		}
+	}
+	t.Run("splits a trailing scope", test("add polish (lang)", "add polish", "lang"))
+	t.Run("ignores descriptions without a scope", test("add polish", "add polish", ""))
+	t.Run("ignores parenthesized phrases", test("add x (and y)", "add x (and y)", ""))
+	t.Run("requires a description", test("(lang)", "(lang)", ""))
+}
+
+func TestTrimmingFooterValues(t *testing.T) {
+	test := func(footer string, expected string) func(*testing.T) {
+		return func(t *testing.T) {
+			actual := TrimFooterValue(footer)
+			if actual != expected {
+				fmt.Printf("expected %q, got %q\n", expected, actual)
+				t.Fail()
+			}
+			cc := CC{Type: "feat", Description: "x", Footers: []string{footer, actual}}
+			parsed, _ := ParseAsMuchOfCCAsPossible(cc.ToString())
+			if fmt.Sprint(parsed.Footers) != fmt.Sprint(cc.Footers) {
+				fmt.Printf("expected %q to round-trip, got %q\n", cc.Footers, parsed.Footers)
+				t.Fail()
+			}
+		}
+	}
+	t.Run("trims around the value", test("Refs:   #1", "Refs: #1"))
+	t.Run("keeps the separator", test("Refs #1", "Refs #1"))
+	t.Run("trims the end of each line", test("Notes: a  \n  b", "Notes: a\n  b"))
+	t.Run("keeps urls intact", test("See: https://example.com/a?b=c", "See: https://example.com/a?b=c"))
+}
+
+func TestRoundTrippingBreakingChanges(t *testing.T) {
+	test := func(message string, bang bool) func(*testing.T) {
+		return func(t *testing.T) {
+			cc, _ := ParseAsMuchOfCCAsPossible(message)
+			if !cc.BreakingChange || cc.Bang != bang {
+				fmt.Printf("expected a breaking change with bang=%v, got %+v\n", bang, cc)
+				t.Fail()
+			}
+			if actual := cc.ToString(); actual != message {
+				fmt.Printf("expected %q to round-trip, got %q\n", message, actual)
+				t.Fail()
+			}
+		}
+	}
+	t.Run("bang only", test("feat!: x\n\n", true))
+	t.Run("footer only", test("feat: x\n\nBREAKING CHANGE: y\n", false))
+	t.Run("both", test("feat!: x\n\nBREAKING CHANGE: y\n", true))
+	cc := CC{Type: "feat", Description: "x", BreakingChange: true}
+	if actual := cc.ToString(); actual != "feat!: x\n\n" {
+		fmt.Printf("unexplained breaking changes need a bang, got %q\n", actual)
+		t.Fail()
+	}
+}
+
+func TestRoundTrippingHeaderSeparators(t *testing.T) {
+	test := func(separator string, message string) func(*testing.T) {
+		return func(t *testing.T) {
+			cc, err := ParseAsMuchOfCCAsPossible(message, WithHeaderSeparator(separator))
+			if err != nil || cc.Type != "feat" || cc.Scope != "cli" || cc.Description != "x" {
+				t.Errorf("expected feat(cli) x, got %+v (%v)", cc, err)
+			}
+			if actual := cc.ToString(WithHeaderSeparator(separator)); actual != message {
+				t.Errorf("expected %q to round-trip, got %q", message, actual)
+			}
+		}
+	}
+	t.Run("standard", test(": ", "feat(cli): x\n\nRefs: #1\n"))
+	t.Run("without a space", test(":", "feat(cli):x\n\nRefs: #1\n"))
+	t.Run("a dash", test(" - ", "feat(cli) - x\n\nRefs: #1\n"))
+	if _, err := ParseHeader("feat(cli): x", WithHeaderSeparator(":")); err != nil {
+		t.Errorf("expected \":\" to also read standard headers, got %v", err)
+	}
+	if _, err := ParseHeader("feat(cli): x", WithHeaderSeparator(" - ")); err == nil {
+		t.Error("expected the standard separator not to match a custom one")
+	}
+	if _, err := ParseHeader("feat(cli) - x"); err == nil {
+		t.Error("expected a custom separator not to leak into later parses")
+	}
+	if _, err := ParseHeader("feat(cli): x", WithHeaderSeparator("")); err != nil {
+		t.Errorf("expected an empty separator to mean the standard one, got %v", err)
+	}
+}
+
+func TestSplittingAtAFooterMarker(t *testing.T) {
+	message := "feat(cli): x\n\nwhy\nNote: not a footer\n---\nRefs: #1\nBREAKING CHANGE: y\n  and z\n"
+	cc, err := ParseWithFooterMarker(message, "---")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cc.Type != "feat" || cc.Scope != "cli" || cc.Description != "x" {
+		t.Errorf("unexpected header %+v", cc)
+	}
+	if cc.Body != "why\nNote: not a footer" {
+		t.Errorf("expected everything before the marker in the body, got %q", cc.Body)
+	}
+	if fmt.Sprint(cc.Footers) != "[Refs: #1 BREAKING CHANGE: y\n  and z]" || !cc.BreakingChange {
+		t.Errorf("expected the footers after the marker, got %q", cc.Footers)
+	}
+	cc, _ = ParseWithFooterMarker("fix: x\n---\nnot a footer\n\nRefs: #2", "---")
+	if cc.Body != "not a footer" || fmt.Sprint(cc.Footers) != "[Refs: #2]" {
+		t.Errorf("expected text after the marker to join the body, got %+v", cc)
+	}
+	standard := "fix: x\n\nbody\n\nRefs: #3\n"
+	for _, marker := range []string{"---", ""} {
+		cc, _ = ParseWithFooterMarker(standard, marker)
+		expected, _ := ParseAsMuchOfCCAsPossible(standard)
+		if fmt.Sprint(cc) != fmt.Sprint(expected) {
+			t.Errorf("expected standard detection without a marker line, got %+v", cc)
+		}
+	}
+}
+
+func TestPreservingTrailingText(t *testing.T) {
+	message := "fix: x\n\nbody\n\nRefs: #1\nReviewed-by: Z\n\n--\nJane Doe\nACME Corp\n"
+	cc, err := ParseAsMuchOfCCAsPossible(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(cc.Footers) != "[Refs: #1 Reviewed-by: Z]" || cc.Trailing != "--\nJane Doe\nACME Corp" {
+		t.Errorf("expected the signature to be split from the footers, got %q and %q", cc.Footers, cc.Trailing)
+	}
+	if cc.ToString() != message {
+		t.Errorf("expected %q to round-trip, got %q", message, cc.ToString())
+	}
+	// a breaking change's explanation may run to several paragraphs
+	cc, _ = ParseAsMuchOfCCAsPossible("feat: x\n\nBREAKING CHANGE: y\n\nand z\n")
+	if fmt.Sprint(cc.Footers) != "[BREAKING CHANGE: y\n\nand z]" || cc.Trailing != "" {
+		t.Errorf("expected the explanation to stay whole, got %q and %q", cc.Footers, cc.Trailing)
+	}
+}
+
+func TestSplittingCommits(t *testing.T) {
+	test := func(full string, header string, body string, footers string, expectedErr error) func(*testing.T) {
+		return func(t *testing.T) {
+			actualHeader, actualBody, actualFooters, err := SplitCommit(full)
+			if actualHeader != header || actualBody != body || actualFooters != footers {
+				fmt.Printf(
+					"expected (%q, %q, %q), got (%q, %q, %q)\n",
+					header, body, footers, actualHeader, actualBody, actualFooters,
+				)
+				t.Fail()
+			}
+			if err != expectedErr {
+				fmt.Printf("expected error %v, got %v\n", expectedErr, err)
+				t.Fail()
+			}
+		}
+	}
+	t.Run("all sections", test(
+		"feat: x\n\nwhy\n  indented\n\nmore\n\nRefs: #1\nNotes: a\n  b\n",
+		"feat: x", "why\n  indented\n\nmore", "Refs: #1\nNotes: a\n  b", nil,
+	))
+	t.Run("missing body", test("feat: x\n\nRefs: #1\n", "feat: x", "", "Refs: #1", nil))
+	t.Run("missing footers", test("feat: x\n\nwhy\n", "feat: x", "why", "", nil))
+	t.Run("header only", test("feat: x", "feat: x", "", "", nil))
+	t.Run("footer-like body lines", test(
+		"feat: x\n\nwhy\nRefs: not a footer\n", "feat: x", "why\nRefs: not a footer", "", nil,
+	))
+	t.Run("unseparated body", test("feat: x\nwhy\n", "feat: x", "why", "", ErrBodyNotSeparated))
+}
+
+func TestParseReturnsPartialResults(t *testing.T) {
+	cc, err := Parse("feat:")
+	if err == nil {
+		fmt.Printf("expected an error for a missing description\n")
+		t.Fail()
+	}
+	if cc.Type != "feat" {
+		fmt.Printf("expected the type to be parsed before the error, got %+v\n", cc)
+		t.Fail()
+	}
+	if cc, err = Parse(validCCWithOnlyHeader); err != nil || cc.Description != "correct spelling of CHANGELOG" {
+		fmt.Printf("unexpected result %+v, %v\n", cc, err)
+		t.Fail()
+	}
+}
+
+func TestParseErrorsLocateTheProblem(t *testing.T) {
+	for message, expected := range map[string]ParseError{
+		"feat:":       {Offset: 4, Line: 1, Column: 5},
+		"feat(cli: x": {Offset: 4, Line: 1, Column: 5},
+	} {
+		_, err := Parse(message)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			fmt.Printf("expected a *ParseError for %q, got %v\n", message, err)
+			t.Fail()
+			continue
+		}
+		if parseErr.Offset != expected.Offset || parseErr.Line != expected.Line || parseErr.Column != expected.Column {
+			fmt.Printf("%q: expected %+v, got %+v\n", message, expected, *parseErr)
+			t.Fail()
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		validCCwithBreakingChangeFooter,
+		validCCWithBreakingChangeBang,
+		validCCwithBothBreakingChangeBangAndFooter,
+		validCCWithOnlyHeader,
+		"",
+		"feat(",
+		"feat(scope)!:",
+		"fix: x\n\nRefs: #1\nBREAKING CHANGE:",
+		"\xff\xfe",
+		"feat: x\r\n\r\nbody\r\n\r\nRefs: #1\r\n",
+		"fix!: x\n\nBREAKING CHANGE: a long\n  folded explanation\nRefs: #2\n",
+		"feat(a)(b): x",
+		"feat:x",
+		"feat: \n",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, message string) {
+		Parse(message) // must not panic
+		ParseStrictly(message)
+		SplitCommit(message)
+		cc, err := ParseHeader(message)
+		if err != nil {
+			return
+		}
+		// valid headers should survive formatting
+		header := strings.SplitN(cc.ToString(), "\n", 2)[0]
+		again, err := ParseHeader(header)
+		if err != nil {
+			t.Fatalf("%q formatted as %q, which doesn't parse: %v", message, header, err)
+		}
+		if again.Type != cc.Type || again.Scope != cc.Scope ||
+			again.Description != cc.Description || again.BreakingChange != cc.BreakingChange {
+			t.Fatalf("%q formatted as %q, which parses as %+v rather than %+v", message, header, again, cc)
+		}
+	})
+}
+
+func FuzzCombinators(f *testing.F) {
+	for _, seed := range []string{"", "abc", "feat(x)", "\r\n", "((", "a:b"} {
+		f.Add(seed)
+	}
+	parsers := []Parser{
+		TakeUntil(Tag(":")),
+		Any(Tag("("), Tag(")"), Empty),
+		Sequence(Tag("feat"), Opt(Delimeted(Tag("("), TakeUntil(Tag(")")), Tag(")")))),
+		Many0(OneOfTheseRunes(" \t")),
+		Many1(LiteralRune('a')),
+		LastRuneOf(Tag("a")),
+		Marked("")(Tag("a")),
+		Regex(`(`),
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, parser := range parsers {
+			// must not panic, and must return a result or an error
+			if result, err := parser([]rune(input)); result == nil && err == nil {
+				t.Fatalf("%q: no result and no error", input)
+			}
+		}
+		if _, err := Marked("")(Tag(""))([]rune(input)); err == nil {
+			t.Fatal("expected an empty mark to be an error")
+		}
+	})
+}
+
+func TestFootersStartLines(t *testing.T) {
+	cc, _ := Parse("fix: x\n\nthis body mentions Refs: #1 mid-line\n\nRefs: #2\n")
+	if len(cc.Footers) != 1 || cc.Footers[0] != "Refs: #2" {
+		fmt.Printf("expected only the line-leading footer, got %q\n", cc.Footers)
+		t.Fail()
+	}
+}
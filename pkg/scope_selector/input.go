@@ -11,12 +11,19 @@ import (
 	"github.com/skalt/git-cc/pkg/single_select"
 )
 
-const emptyScopeTemplate = "scopes:\n%s\n"
-const newScopeTemplate = "  %s: description of what short-form \"%s\" represents"
-
 type Model struct {
 	input   single_select.Model
 	helpBar helpbar.Model
+	// the scope_max_length and header_max_length to count against; a
+	// maxLength of 0 hides the counter
+	maxLength       int
+	headerMaxLength int
+	commitType      string
+	separator       string // the header_separator
+	// the config and sorted scopes to offer, narrowed by scopes_by_type once
+	// the commit type is known
+	cfg    config.Cfg
+	scopes []map[string]string
 }
 
 // the method for determining if the current input matches an option.
@@ -54,11 +61,12 @@ func makeOptHintPair(options []map[string]string) ([]string, []string) {
 }
 
 func NewModel(cc *parser.CC, cfg config.Cfg) Model {
+	scopes := cfg.SortedScopes()
 	return Model{
 		single_select.NewModel(
-			config.Faint("select a scope:"),
+			config.Faint(cfg.Prompt(config.PromptScope)),
 			cc.Scope,
-			makeOptions(cfg.Scopes),
+			makeOptions(cfg.AllowedScopes(cc.Type, scopes)),
 			match,
 		),
 		helpbar.NewModel(
@@ -67,18 +75,68 @@ func NewModel(cc *parser.CC, cfg config.Cfg) Model {
 			config.HelpBack,
 			config.HelpCancel,
 		),
+		cfg.ScopeMaxLength,
+		cfg.HeaderMaxLength,
+		cc.Type,
+		cfg.Separator(),
+		cfg,
+		scopes,
+	}
+}
+
+// register an error with the selector
+func (m Model) SetErr(err error) Model {
+	m.input = m.input.SetErr(err)
+	return m
+}
+
+// set the chosen commit type, which counts towards the header's length and
+// narrows the options to its scopes_by_type
+func (m Model) SetType(commitType string) Model {
+	current := m.Value()
+	m.commitType = commitType
+	m.input = m.input.SetOptions(makeOptions(m.cfg.AllowedScopes(commitType, m.scopes)))
+	m.input = m.input.Highlight(current)
+	return m
+}
+
+// a styled length-counter for the selected scope and what it leaves of the
+// header for the description, e.g. (scope 3/12, 62 left for the description)
+func (m Model) viewCounter() string {
+	scope := 0
+	if value := m.Value(); value != "new scope" {
+		scope = config.Width(value)
+	}
+	prefix := config.Width(m.commitType) + config.Width(m.separator)
+	if scope > 0 {
+		prefix += scope + len("()")
 	}
+	left := m.headerMaxLength - prefix
+	view := fmt.Sprintf("(scope %d/%d, %d left for the description)", scope, m.maxLength, left)
+	if scope > m.maxLength || left < 0 {
+		return config.Underline(view)
+	}
+	return config.Faint(view)
 }
 
 func (m Model) Value() string {
 	return m.input.Value()
 }
 
+// pre-select the `scope`, e.g. the chosen commit type's default
+func (m Model) Highlight(scope string) Model {
+	m.input = m.input.Highlight(scope)
+	return m
+}
+
 func (m Model) View() string {
 	s := strings.Builder{}
 	s.WriteString(m.input.View())
 	s.WriteRune('\n')
 	s.WriteString(m.helpBar.View())
+	if m.maxLength > 0 {
+		s.WriteString(" " + m.viewCounter())
+	}
 	return s.String()
 }
 
@@ -89,15 +147,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		switch msg.Type {
 		case tea.KeyEnter, tea.KeyTab:
 			if m.Value() == "new scope" {
-				newScope := m.input.CurrentInput()
-				cfg := config.EditCfgFile(
-					config.CentralStore,
-					config.ExampleCfgFileHeader+config.ExampleCfgFileCommitTypes+"\n"+fmt.Sprintf(
-						emptyScopeTemplate,
-						fmt.Sprintf(newScopeTemplate, newScope, newScope),
-					),
-				)
-				values, hints := makeOptHintPair(makeOptions(cfg.Scopes))
+				cfg, err := config.EditCfgFile(config.CentralStore)
+				if err != nil {
+					m.input = m.input.SetErr(err)
+					return m, cmd
+				}
+				m.cfg, m.scopes = cfg, cfg.SortedScopes()
+				values, hints := makeOptHintPair(makeOptions(cfg.AllowedScopes(m.commitType, m.scopes)))
 				m.input.Options = values
 				m.input.Hints = hints
 				if m.input.Cursor >= len(m.input.Options) {
@@ -1,6 +1,7 @@
 package single_select
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -9,6 +10,8 @@ import (
 	"github.com/muesli/reflow/padding"
 	"github.com/muesli/reflow/wordwrap"
 	term "github.com/muesli/termenv"
+
+	"github.com/skalt/git-cc/pkg/config"
 )
 
 type Model struct {
@@ -23,6 +26,10 @@ type Model struct {
 	Width           int // in runes
 	Height          int // in lines
 	textInput       textinput.Model
+	// whether the first nine options are numbered for picking with 1-9
+	numbered bool
+	// option -> the label to show instead of it
+	labels map[string]string
 }
 
 func (m Model) Init() tea.Cmd {
@@ -36,7 +43,7 @@ func NewModel(context string, value string, options []map[string]string, match f
 			values, hints = append(values, value), append(hints, hint)
 		}
 	}
-	input := textinput.NewModel()
+	input := config.NewTextInput()
 	input.Placeholder = "type to select"
 	input.Prompt = "   "
 	input.SetValue(value)
@@ -54,6 +61,54 @@ func NewModel(context string, value string, options []map[string]string, match f
 	return result
 }
 
+// number the first nine matched options so that pressing a digit moves the
+// cursor to that option, unless something's been typed to filter them
+func (m Model) SetNumbered(numbered bool) Model {
+	m.numbered = numbered
+	return m
+}
+
+// show each option in `labels` as its label, e.g. "✨ feature" for "feat".
+// Options still match what's typed by their value, and Value is unaffected.
+func (m Model) SetLabels(labels map[string]string) Model {
+	m.labels = labels
+	m.maxOptionLength = 0
+	return m
+}
+
+// how `option` is shown: its label, if it has one
+func (m Model) display(option string) string {
+	if label, ok := m.labels[option]; ok {
+		return label
+	}
+	return option
+}
+
+// the number to press to pick matched option `i`, or as many spaces for
+// options past the ninth
+func (m Model) label(i int) string {
+	switch {
+	case !m.numbered:
+		return ""
+	case i < 9:
+		return fmt.Sprintf("%d ", i+1)
+	default:
+		return "  "
+	}
+}
+
+// the index of the matched option that pressing `msg` picks, if any
+func (m Model) picked(msg tea.KeyMsg) (int, bool) {
+	if !m.numbered || m.textInput.Value() != "" || msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return 0, false
+	}
+	digit := msg.Runes[0]
+	if digit < '1' || digit > '9' || int(digit-'1') >= len(m.matched) {
+		return 0, false
+	}
+	return int(digit - '1'), true
+}
+
 func (m *Model) Focus() tea.Cmd {
 	return m.textInput.Focus()
 }
@@ -82,14 +137,92 @@ func (m Model) maxOptLen() int {
 	}
 	max := 0
 	for _, opt := range m.Options {
-		if len(opt) > max {
-			max = len(opt)
+		if width := config.Width(m.display(opt)); width > max {
+			max = width
 		}
 	}
 	m.maxOptionLength = max
 	return max
 }
 
+// the lines around the options: the context, input, error, page indicator, and
+// the help bar below the selector.
+const reservedLines = 6
+
+// how many rows of options fit on a page, or all of the options before the
+// terminal's height is known.
+func (m Model) rows() int {
+	rows := len(m.Options)
+	if m.Height > 0 {
+		rows = m.Height - reservedLines
+	}
+	if rows < 1 {
+		return 1
+	}
+	return rows
+}
+
+// the width of each column of the grid: the gutter, the longest option, and a
+// gap
+func (m Model) cellWidth() int {
+	return 3 + len(m.label(0)) + m.maxOptLen() + 2
+}
+
+// how many columns to lay the matched options out in. Options that fit on a
+// page, or in a terminal too narrow for two columns, take a single column
+// with their hints.
+func (m Model) columns() int {
+	if m.Width == 0 || len(m.matched) <= m.rows() {
+		return 1
+	}
+	if columns := m.Width / m.cellWidth(); columns > 1 {
+		return columns
+	}
+	return 1
+}
+
+// how many options fit on a page. In a grid, one row is left for the
+// selected option's hint.
+func (m Model) pageSize() int {
+	columns := m.columns()
+	if columns == 1 {
+		return m.rows()
+	}
+	if rows := m.rows() - 1; rows > 1 {
+		return rows * columns
+	}
+	return columns
+}
+
+// the 1-based page of the cursor and the number of pages of matched options
+func (m Model) Page() (page int, pages int) {
+	size := m.pageSize()
+	pages = (len(m.matched) + size - 1) / size
+	if pages == 0 {
+		pages = 1
+	}
+	return m.Cursor/size + 1, pages
+}
+
+// the matched options on the cursor's page, followed by as many rejected
+// options as fit on the last page.
+func (m Model) visible() (matched [][2]string, filtered [][2]string) {
+	size := m.pageSize()
+	start := (m.Cursor / size) * size
+	end := start + size
+	if end > len(m.matched) {
+		end = len(m.matched)
+	}
+	matched = m.matched[start:end]
+	if rest := size - len(matched); rest > 0 && m.columns() == 1 {
+		if rest > len(m.filtered) {
+			rest = len(m.filtered)
+		}
+		filtered = m.filtered[:rest]
+	}
+	return matched, filtered
+}
+
 func MatchStart(m *Model, query string, option string) bool {
 	return len(query) <= len(option) && option[0:len(query)] == query
 }
@@ -107,6 +240,20 @@ func (m Model) filter(startingWith string) ([][2]string, [][2]string) {
 	return matched, filtered
 }
 
+// replace the options, keeping what's been typed to filter them. The cursor
+// moves back to the first match.
+func (m Model) SetOptions(options []map[string]string) Model {
+	m.Options, m.Hints = []string{}, []string{}
+	for _, option := range options {
+		for value, hint := range option {
+			m.Options, m.Hints = append(m.Options, value), append(m.Hints, hint)
+		}
+	}
+	m.matched, m.filtered = m.filter(m.textInput.Value())
+	m.Cursor = 0
+	return m
+}
+
 // access the matched, selected value. If no value is matched, this returns "".
 func (m Model) Value() string {
 	if len(m.matched) > 0 {
@@ -116,10 +263,49 @@ func (m Model) Value() string {
 	}
 }
 
+// move the cursor to the option `value` if nothing has been typed to filter
+// the options. Unknown values leave the cursor in place.
+func (m Model) Highlight(value string) Model {
+	if m.textInput.Value() != "" {
+		return m
+	}
+	for i, match := range m.matched {
+		if match[0] == value {
+			m.Cursor = i
+			break
+		}
+	}
+	return m
+}
+
 func (m Model) CurrentInput() string {
 	return m.textInput.Value()
 }
 
+// move the `cursor` one step in a grid of `n` options laid out row by row in
+// `columns` columns, staying put at the grid's edges.
+func moveInGrid(cursor int, n int, columns int, key tea.KeyType) int {
+	next := cursor
+	switch key {
+	case tea.KeyUp:
+		next = cursor - columns
+	case tea.KeyDown:
+		next = cursor + columns
+	case tea.KeyLeft:
+		if cursor%columns > 0 {
+			next = cursor - 1
+		}
+	case tea.KeyRight:
+		if cursor%columns < columns-1 {
+			next = cursor + 1
+		}
+	}
+	if next < 0 || next >= n {
+		return cursor
+	}
+	return next
+}
+
 func Update(msg tea.Msg, model Model) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -127,6 +313,19 @@ func Update(msg tea.Msg, model Model) (Model, tea.Cmd) {
 		switch msg.Type {
 		case tea.KeyCtrlC:
 			return model, tea.Quit
+		case tea.KeyRunes:
+			if i, ok := model.picked(msg); ok {
+				model.textInput.Err = nil
+				model.Cursor = i
+				return model, cmd
+			}
+		case tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight:
+			if columns := model.columns(); columns > 1 {
+				model.Cursor = moveInGrid(model.Cursor, len(model.matched), columns, msg.Type)
+				return model, cmd
+			}
+		}
+		switch msg.Type {
 		case tea.KeyUp:
 			if model.Cursor > 0 {
 				model.Cursor -= 1
@@ -141,7 +340,21 @@ func Update(msg tea.Msg, model Model) (Model, tea.Cmd) {
 				model.Cursor = 0
 			}
 			return model, cmd
+		case tea.KeyPgUp:
+			if model.Cursor -= model.pageSize(); model.Cursor < 0 {
+				model.Cursor = 0
+			}
+			return model, cmd
+		case tea.KeyPgDown:
+			if model.Cursor += model.pageSize(); model.Cursor > len(model.matched)-1 {
+				model.Cursor = len(model.matched) - 1
+			}
+			if model.Cursor < 0 {
+				model.Cursor = 0
+			}
+			return model, cmd
 		default:
+			model.textInput.Err = nil
 			model.textInput, cmd = model.textInput.Update(msg)
 			model.matched, model.filtered = model.filter(model.textInput.Value())
 			model.Cursor = 0
@@ -176,11 +389,39 @@ func wrapLine(
 	return result
 }
 
+// lay out `matched`, which starts at option `first`, in `columns` columns,
+// followed by the selected option's hint and the page indicator.
+func (m Model) viewGrid(matched [][2]string, first int, columns int) string {
+	s := strings.Builder{}
+	width := uint(m.cellWidth() - 3 - len(m.label(0)))
+	for i, match := range matched {
+		opt := m.label(first+i) + padding.String(m.display(match[0]), width)
+		if m.Cursor == first+i {
+			s.WriteString(" > " + config.Style(opt).Underline().Bold().String())
+		} else {
+			s.WriteString("   " + opt)
+		}
+		if i%columns == columns-1 || i == len(matched)-1 {
+			s.WriteString("\n")
+		}
+	}
+	if len(m.matched) > 0 {
+		s.WriteString("   " + config.Faint(m.matched[m.Cursor][1]) + "\n")
+	}
+	if page, pages := m.Page(); pages > 1 {
+		s.WriteString("   " + config.Faint(fmt.Sprintf("page %d/%d", page, pages)) + "\n")
+	}
+	return s.String()
+}
+
 func (m Model) View() string {
 	s := strings.Builder{}
 	s.WriteString(m.context + "\n")
 	s.WriteString(m.textInput.View() + "\n")
-	leftGutter := 3 // "   "
+	if m.textInput.Err != nil {
+		s.WriteString("   " + config.Underline(m.textInput.Err.Error()) + "\n")
+	}
+	leftGutter := 3 + len(m.label(0)) // "   " and any number
 	maxOptLen := m.maxOptLen()
 	leftColumn := (leftGutter + maxOptLen) + 1 // for the space
 	rightColumn := m.Width - leftColumn
@@ -191,33 +432,41 @@ func (m Model) View() string {
 		}
 		return s
 	}
-	for i, match := range m.matched {
-		opt, hint := pad(match[0], maxOptLen), " "+match[1]
-		if m.Cursor == i {
+	matched, filtered := m.visible()
+	first := (m.Cursor / m.pageSize()) * m.pageSize()
+	if columns := m.columns(); columns > 1 {
+		s.WriteString(m.viewGrid(matched, first, columns))
+		return s.String()
+	}
+	for i, match := range matched {
+		opt, hint := pad(m.display(match[0]), maxOptLen), " "+match[1]
+		if m.Cursor == first+i {
 			style := func(str string) term.Style {
-				return term.String(str).Underline()
+				return config.Style(str).Underline()
 			}
-			s.WriteString(" > " + style(opt).Bold().String())
+			s.WriteString(" > " + m.label(first+i) + style(opt).Bold().String())
 			s.WriteString(wrapLine(uint(leftColumn), hint, rightColumn, style))
 		} else {
 			style := func(str string) term.Style {
-				return term.String(str).Faint()
+				return config.Style(str).Faint()
 			}
-			s.WriteString("   " + opt)
+			s.WriteString("   " + m.label(first+i) + opt)
 			s.WriteString(wrapLine(uint(leftColumn), hint, rightColumn, style))
 		}
 		s.WriteString("\n")
 	}
 	// s.WriteString("\n")
 	style := func(str string) term.Style {
-		return term.String(str).Faint()
+		return config.Style(str).Faint()
 	}
-	for _, rejected := range m.filtered {
-		opt, hint := style(pad(rejected[0], maxOptLen)).String(), rejected[1]
-		s.WriteString("   " + opt + " ")
+	for _, rejected := range filtered {
+		opt, hint := style(pad(m.display(rejected[0]), maxOptLen)).String(), rejected[1]
+		s.WriteString("   " + strings.Repeat(" ", len(m.label(0))) + opt + " ")
 		s.WriteString(wrapLine(uint(leftColumn), hint, rightColumn, style))
 		s.WriteString("\n")
 	}
-
+	if page, pages := m.Page(); pages > 1 {
+		s.WriteString("   " + config.Faint(fmt.Sprintf("page %d/%d", page, pages)) + "\n")
+	}
 	return s.String()
 }
@@ -0,0 +1,180 @@
+package single_select
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPaginatingManyOptions(t *testing.T) {
+	options := []map[string]string{}
+	for i := 0; i < 200; i++ {
+		options = append(options, map[string]string{fmt.Sprintf("scope-%03d", i): "a generated scope"})
+	}
+	m := NewModel("select a scope:", "", options, MatchStart)
+	if page, pages := m.Page(); page != 1 || pages != 1 {
+		t.Errorf("expected a single page before the height is known, got %d/%d", page, pages)
+	}
+	// without a width, the options keep to a single column
+	m, _ = m.Update(tea.WindowSizeMsg{Height: 26})
+	size := 26 - reservedLines
+	if page, pages := m.Page(); page != 1 || pages != 10 {
+		t.Errorf("expected page 1/10, got %d/%d", page, pages)
+	}
+	if lines := strings.Count(m.View(), "\n"); lines > 26-2 {
+		t.Errorf("expected the view to leave room for the help bar, got %d lines", lines)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.Cursor != size || m.Value() != "scope-020" {
+		t.Errorf("expected pgdn to move a page down, got %d (%s)", m.Cursor, m.Value())
+	}
+	if !strings.Contains(m.View(), "page 2/10") || strings.Contains(m.View(), "scope-019") {
+		t.Errorf("expected only the second page, got %q", m.View())
+	}
+	for i := 0; i < 20; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	}
+	if m.Value() != "scope-199" {
+		t.Errorf("expected pgdn to stop at the last option, got %s", m.Value())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if page, _ := m.Page(); page != 1 {
+		t.Errorf("expected wrapping around to return to the first page, got %d", page)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.Cursor != 0 {
+		t.Errorf("expected pgup to stop at the first option, got %d", m.Cursor)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("scope-1")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if page, pages := m.Page(); page != 2 || pages != 5 || m.Value() != "scope-120" {
+		t.Errorf("expected filtering to paginate the 100 matches, got %d/%d (%s)", page, pages, m.Value())
+	}
+}
+
+func TestNavigatingColumns(t *testing.T) {
+	options := []map[string]string{}
+	for i := 0; i < 60; i++ {
+		options = append(options, map[string]string{fmt.Sprintf("scope-%02d", i): "a generated scope"})
+	}
+	m := NewModel("select a scope:", "", options, MatchStart)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 16})
+	columns := m.columns()
+	if columns != 80/(3+8+2) {
+		t.Errorf("expected %d columns, got %d", 80/(3+8+2), columns)
+	}
+	if page, pages := m.Page(); page != 1 || pages != 2 {
+		t.Errorf("expected page 1/2, got %d/%d", page, pages)
+	}
+	if view := m.View(); !strings.Contains(view, "scope-01") || strings.Count(view, "a generated scope") != 1 {
+		t.Errorf("expected a grid with only the selected option's hint, got %q", view)
+	}
+	steps := []struct {
+		key      tea.KeyType
+		expected int
+	}{
+		{tea.KeyLeft, 0},
+		{tea.KeyUp, 0},
+		{tea.KeyRight, 1},
+		{tea.KeyDown, 1 + columns},
+		{tea.KeyLeft, columns},
+		{tea.KeyLeft, columns},
+		{tea.KeyUp, 0},
+	}
+	for _, step := range steps {
+		m, _ = m.Update(tea.KeyMsg{Type: step.key})
+		if m.Cursor != step.expected {
+			t.Errorf("expected %v to move to %d, got %d", step.key, step.expected, m.Cursor)
+		}
+	}
+	for i := 0; i < columns-1; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.Cursor != columns-1 {
+		t.Errorf("expected right to stop at the end of the row, got %d", m.Cursor)
+	}
+	for i := 0; i < 20; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	last := columns - 1
+	for last+columns < 60 {
+		last += columns
+	}
+	if m.Cursor != last {
+		t.Errorf("expected down to stop at the last row, got %d", m.Cursor)
+	}
+	if page, _ := m.Page(); page != 2 {
+		t.Errorf("expected moving down to reach the second page, got %d", page)
+	}
+}
+
+func TestNarrowTerminalsKeepASingleColumn(t *testing.T) {
+	options := []map[string]string{}
+	for i := 0; i < 60; i++ {
+		options = append(options, map[string]string{fmt.Sprintf("scope-%02d", i): "a generated scope"})
+	}
+	m := NewModel("select a scope:", "", options, MatchStart)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 20, Height: 16})
+	if columns := m.columns(); columns != 1 {
+		t.Errorf("expected a single column, got %d", columns)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.Cursor != 1 {
+		t.Errorf("expected down to move to the next option, got %d", m.Cursor)
+	}
+	if view := m.View(); !strings.Contains(view, "generated\n") || strings.Contains(view, "scope-10") {
+		t.Errorf("expected a single column of options with their hints, got %q", view)
+	}
+}
+
+func TestPickingNumberedOptions(t *testing.T) {
+	options := []map[string]string{}
+	for i := 0; i < 12; i++ {
+		options = append(options, map[string]string{fmt.Sprintf("type%02d", i): "a generated type"})
+	}
+	key := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+	m := NewModel("select a type:", "", options, MatchStart).SetNumbered(true)
+	if view := m.View(); !strings.Contains(view, " > 1 ") || !strings.Contains(view, "   9 type08") ||
+		!strings.Contains(view, "     type09") {
+		t.Errorf("expected the first nine options to be numbered, got:\n%s", view)
+	}
+	m, _ = m.Update(key('3'))
+	if m.Value() != "type02" || m.CurrentInput() != "" {
+		t.Errorf("expected 3 to pick the third option, got %q (typed %q)", m.Value(), m.CurrentInput())
+	}
+	m, _ = m.Update(key('0')) // isn't a number to pick, so it filters
+	if m.CurrentInput() != "0" || m.Value() != "" {
+		t.Errorf("expected 0 to be typed, got %q (%q)", m.CurrentInput(), m.Value())
+	}
+	// once something's typed, digits filter the options
+	m = NewModel("select a type:", "", options, MatchStart).SetNumbered(true)
+	for _, r := range "type1" {
+		m, _ = m.Update(key(r))
+	}
+	if m.Value() != "type10" {
+		t.Errorf("expected the digit to filter the options, got %q", m.Value())
+	}
+	// without numbering, digits are typed
+	m, _ = NewModel("select a type:", "", options, MatchStart).Update(key('3'))
+	if m.CurrentInput() != "3" || strings.Contains(m.View(), "1 type00") {
+		t.Errorf("expected 3 to be typed, got %q", m.CurrentInput())
+	}
+}
+
+func TestShowingLabels(t *testing.T) {
+	options := []map[string]string{{"feat": "adds a feature"}, {"fix": "fixes a bug"}}
+	m := NewModel("select a type:", "", options, MatchStart).SetLabels(map[string]string{"feat": "✨ feature"})
+	if view := m.View(); !strings.Contains(view, "✨ feature") || strings.Contains(view, "feat ") {
+		t.Errorf("expected feat's label instead of feat, got:\n%s", view)
+	}
+	if m.Value() != "feat" {
+		t.Errorf("expected the value to stay feat, got %q", m.Value())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("fi")})
+	if m.Value() != "fix" || !strings.Contains(m.View(), "fix ") {
+		t.Errorf("expected unlabeled options to show their value, got %q:\n%s", m.Value(), m.View())
+	}
+}
@@ -18,9 +18,9 @@ type Model struct {
 func NewModel(cc *parser.CC, cfg config.Cfg) Model {
 	return Model{
 		single_select.NewModel(
-			config.Faint("select a commit type: "), cc.Type, cfg.CommitTypes,
+			config.Faint(cfg.Prompt(config.PromptCommitType)), cc.Type, cfg.CommitTypes,
 			single_select.MatchStart,
-		),
+		).SetNumbered(cfg.NumberedTypes).SetLabels(cfg.TypeLabels),
 		helpbar.NewModel(
 			config.HelpSubmit, config.HelpSelect, config.HelpCancel,
 		),
@@ -31,6 +31,12 @@ func (m Model) Value() string {
 	return m.input.Value()
 }
 
+// register an error to display alongside the options
+func (m Model) SetErr(err error) Model {
+	m.input = m.input.SetErr(err)
+	return m
+}
+
 func (m Model) View() string {
 	s := strings.Builder{}
 	s.WriteString(m.input.View())